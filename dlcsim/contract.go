@@ -0,0 +1,198 @@
+package dlcsim
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/YusukeShimizu/c-go-bitcoin/ecc"
+	"golang.org/x/xerrors"
+)
+
+// Outcome is what a CET pays out for, as the outcomes the oracle signs
+// with its first len(Outcome) nonces: a single outcome of an enumerated
+// event, or a prefix of the digits of a numeric event, which covers every
+// value that starts with those digits.
+type Outcome []string
+
+// maxValue returns base**digits - 1, the largest value a numeric event
+// with those parameters can attest.
+func maxValue(base, digits int) (uint64, error) {
+	if base < 2 || digits < 1 {
+		return 0, xerrors.Errorf("numeric event needs a base of at least 2 and a digit, got base %d and %d digits", base, digits)
+	}
+	max := uint64(1)
+	for i := 0; i < digits; i++ {
+		if max > math.MaxUint64/uint64(base) {
+			return 0, xerrors.Errorf("%d digits in base %d do not fit in 64 bits", digits, base)
+		}
+		max *= uint64(base)
+	}
+	return max - 1, nil
+}
+
+// digitOutcomes returns the digits of value in base, most significant
+// first and padded to digits.
+func digitOutcomes(value uint64, base, digits int) (Outcome, error) {
+	max, err := maxValue(base, digits)
+	if err != nil {
+		return nil, err
+	}
+	if value > max {
+		return nil, xerrors.Errorf("%d does not fit in %d digits in base %d", value, digits, base)
+	}
+	out := make(Outcome, digits)
+	for i := digits - 1; i >= 0; i-- {
+		out[i] = strconv.FormatUint(value%uint64(base), 10)
+		value /= uint64(base)
+	}
+	return out, nil
+}
+
+// DigitPrefixes returns the fewest digit prefixes that together cover
+// exactly the values in [start, end] of a numeric event, so that one CET
+// with an adaptor signature per prefix pays out for the whole range. Each
+// prefix covers an aligned block of base**k values; the greedy choice of
+// the largest block that fits at each step is optimal. Prefixes have at
+// least one digit, since a CET needs at least one oracle signature.
+func DigitPrefixes(start, end uint64, base, digits int) ([]Outcome, error) {
+	max, err := maxValue(base, digits)
+	if err != nil {
+		return nil, err
+	}
+	if start > end || end > max {
+		return nil, xerrors.Errorf("range [%d, %d] is not within [0, %d]", start, end, max)
+	}
+	b := uint64(base)
+	var prefixes []Outcome
+	for v := start; ; {
+		// the largest block of base**k values that starts at v and ends by end.
+		k, size := 0, uint64(1)
+		for k < digits-1 && v%(size*b) == 0 && size*b-1 <= end-v {
+			k++
+			size *= b
+		}
+		full, _ := digitOutcomes(v, base, digits)
+		prefixes = append(prefixes, full[:digits-k])
+		if end-v < size {
+			return prefixes, nil
+		}
+		v += size
+	}
+}
+
+// AdaptorPoint returns the point whose discrete log the attestation of o
+// reveals: the sum of the signature points R_i + e_i*P of its outcomes.
+// A CET adaptor signature for this point becomes a valid signature only
+// when the oracle attests to o.
+func (a *Announcement) AdaptorPoint(o Outcome) (*ecc.PublicKey, error) {
+	if err := a.checkPrefix(o); err != nil {
+		return nil, err
+	}
+	pub, err := ecc.PublicKeyPoint(a.PublicKey)
+	if err != nil {
+		return nil, xerrors.Errorf("oracle key: %w", err)
+	}
+	sum := ecc.S256Infinity()
+	for i, outcome := range o {
+		nonce, err := ecc.PublicKeyPoint(a.Nonces[i])
+		if err != nil {
+			return nil, xerrors.Errorf("nonce %d: %w", i, err)
+		}
+		point, err := ecc.SchnorrSignaturePoint(pub, nonce, attestationMessage(outcome))
+		if err != nil {
+			return nil, err
+		}
+		sum = sum.Add(point)
+	}
+	if sum.IsInfinity() {
+		return nil, xerrors.New("signature points add up to infinity")
+	}
+	return sum.ToECDSA(), nil
+}
+
+// checkPrefix reports whether o is an outcome a CET can pay out for.
+func (a *Announcement) checkPrefix(o Outcome) error {
+	if len(o) == 0 || len(o) > len(a.Nonces) {
+		return xerrors.Errorf("outcome has %d parts for %d nonces", len(o), len(a.Nonces))
+	}
+	if a.Base == 0 && len(o) != 1 {
+		return xerrors.Errorf("outcome of enumerated event %q has %d parts", a.EventID, len(o))
+	}
+	for i, outcome := range o {
+		if err := a.checkOutcome(i, outcome); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Secret returns the discrete log of the adaptor point of o, the sum of
+// the s values of the signatures of its outcomes. It verifies att against
+// a first and returns an error wrapping ErrInvalidAttestation if it is not
+// a valid attestation of the event, since unchecked s values would give a
+// wrong secret. It returns ErrOutcomeMismatch if att attests to an outcome
+// that o does not cover.
+func (a *Announcement) Secret(att *Attestation, o Outcome) (*ecc.Scalar, error) {
+	if err := a.Verify(att); err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, ErrInvalidAttestation)
+	}
+	if err := a.checkPrefix(o); err != nil {
+		return nil, err
+	}
+	secret := new(ecc.Scalar)
+	for i, outcome := range o {
+		if att.Outcomes[i] != outcome {
+			return nil, xerrors.Errorf("part %d is %q, attested %q: %w", i, outcome, att.Outcomes[i], ErrOutcomeMismatch)
+		}
+		var s ecc.Scalar
+		if s.SetByteSlice(att.Signatures[i].Bytes()[32:]) {
+			return nil, xerrors.Errorf("s of signature %d is not below n: %w", i, ErrInvalidAttestation)
+		}
+		secret.Add(secret, &s)
+	}
+	return secret, nil
+}
+
+// SignCET returns the adaptor signature of the CET with the given sighash
+// that pays out for o. The counterparty checks it with VerifyCET and can
+// complete it once the oracle attests to o.
+func (a *Announcement) SignCET(key *ecc.PrivateKey, o Outcome, sighash []byte) (*ecc.ECDSAAdaptorSignature, error) {
+	pt, err := a.AdaptorPoint(o)
+	if err != nil {
+		return nil, err
+	}
+	adaptor, err := ecc.PublicKeyPoint(pt)
+	if err != nil {
+		return nil, err
+	}
+	return key.SignECDSAAdaptor(sighash, adaptor, nil)
+}
+
+// VerifyCET reports whether sig is an adaptor signature by pub of the CET
+// with the given sighash for o.
+func (a *Announcement) VerifyCET(pub *ecc.PublicKey, o Outcome, sighash []byte, sig *ecc.ECDSAAdaptorSignature) (bool, error) {
+	pt, err := a.AdaptorPoint(o)
+	if err != nil {
+		return false, err
+	}
+	adaptor, err := ecc.PublicKeyPoint(pt)
+	if err != nil {
+		return false, err
+	}
+	p, err := ecc.PublicKeyPoint(pub)
+	if err != nil {
+		return false, err
+	}
+	return p.VerifyECDSAAdaptor(sighash, adaptor, sig)
+}
+
+// CompleteCET returns the signature of the CET for o from its adaptor
+// signature, which is only possible once the oracle has attested to o. att
+// is verified against a as in Secret.
+func (a *Announcement) CompleteCET(att *Attestation, o Outcome, sig *ecc.ECDSAAdaptorSignature) (*ecc.Signature, error) {
+	secret, err := a.Secret(att, o)
+	if err != nil {
+		return nil, err
+	}
+	return sig.Complete(secret), nil
+}
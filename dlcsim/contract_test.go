@@ -0,0 +1,201 @@
+package dlcsim
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/YusukeShimizu/c-go-bitcoin/ecc"
+)
+
+func TestDigitPrefixes(t *testing.T) {
+	tests := []struct {
+		start, end   uint64
+		base, digits int
+		want         []Outcome
+	}{
+		{0, 15, 2, 4, []Outcome{{"0"}, {"1"}}},
+		{5, 5, 2, 4, []Outcome{{"0", "1", "0", "1"}}},
+		{1, 14, 2, 4, []Outcome{
+			{"0", "0", "0", "1"}, {"0", "0", "1"}, {"0", "1"},
+			{"1", "0"}, {"1", "1", "0"}, {"1", "1", "1", "0"},
+		}},
+		{100, 499, 10, 3, []Outcome{{"1"}, {"2"}, {"3"}, {"4"}}},
+		{95, 205, 10, 3, []Outcome{
+			{"0", "9", "5"}, {"0", "9", "6"}, {"0", "9", "7"}, {"0", "9", "8"}, {"0", "9", "9"},
+			{"1"},
+			{"2", "0", "0"}, {"2", "0", "1"}, {"2", "0", "2"}, {"2", "0", "3"}, {"2", "0", "4"}, {"2", "0", "5"},
+		}},
+		{0, 1<<63 - 1, 2, 63, []Outcome{{"0"}, {"1"}}},
+	}
+	for _, tt := range tests {
+		got, err := DigitPrefixes(tt.start, tt.end, tt.base, tt.digits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("DigitPrefixes(%d, %d, %d, %d) = %v, want %v", tt.start, tt.end, tt.base, tt.digits, got, tt.want)
+		}
+	}
+	for _, tt := range []struct{ start, end uint64 }{{5, 4}, {0, 16}} {
+		if _, err := DigitPrefixes(tt.start, tt.end, 2, 4); err == nil {
+			t.Errorf("DigitPrefixes(%d, %d) succeeded", tt.start, tt.end)
+		}
+	}
+}
+
+// cet is a contract execution transaction as far as signing is concerned.
+type cet struct {
+	outcome Outcome
+	sighash []byte
+	sig     *ecc.ECDSAAdaptorSignature
+}
+
+func TestNumericContract(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	o := newTestOracle(t)
+	a, err := o.AnnounceNumeric("btcusd", 2, 10, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, _ := ecc.NewPrivateKey(big.NewInt(0xa11ce))
+	bob, _ := ecc.NewPrivateKey(big.NewInt(0xb0b))
+
+	// three payout ranges, one CET each and an adaptor signature by alice
+	// for every prefix of its range.
+	var cets []cet
+	for i, rng := range [][2]uint64{{0, 99}, {100, 499}, {500, 1023}} {
+		sighash := sha256.Sum256([]byte(fmt.Sprintf("CET %d", i)))
+		prefixes, err := DigitPrefixes(rng[0], rng[1], a.Base, len(a.Nonces))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, prefix := range prefixes {
+			sig, err := a.SignCET(alice, prefix, sighash[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			cets = append(cets, cet{prefix, sighash[:], sig})
+		}
+	}
+	alicePub := alice.Public().(*ecc.PublicKey)
+	for _, c := range cets {
+		if ok, err := a.VerifyCET(alicePub, c.outcome, c.sighash, c.sig); err != nil || !ok {
+			t.Fatalf("VerifyCET(%v) = %v, %v, want true", c.outcome, ok, err)
+		}
+		if ok, _ := a.VerifyCET(bob.Public().(*ecc.PublicKey), c.outcome, c.sighash, c.sig); ok {
+			t.Fatalf("VerifyCET(%v) accepted the wrong key", c.outcome)
+		}
+	}
+
+	att, err := o.AttestNumeric("btcusd", 321)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Verify(att); err != nil {
+		t.Fatal(err)
+	}
+	// bob can complete exactly one CET: the one of the prefix 321 starts with.
+	pub, _ := ecc.PublicKeyPoint(alicePub)
+	completed := 0
+	for _, c := range cets {
+		sig, err := a.CompleteCET(att, c.outcome, c.sig)
+		if errors.Is(err, ErrOutcomeMismatch) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		completed++
+		if want := sha256.Sum256([]byte("CET 1")); !bytes.Equal(c.sighash, want[:]) {
+			t.Errorf("completed the CET of %v", c.outcome)
+		}
+		if ok, err := pub.Verify(new(big.Int).SetBytes(c.sighash), *sig); err != nil || !ok {
+			t.Errorf("completed signature for %v does not verify", c.outcome)
+		}
+	}
+	if completed != 1 {
+		t.Errorf("completed %d CETs, want 1", completed)
+	}
+}
+
+func TestEnumContract(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	o := newTestOracle(t)
+	a, err := o.AnnounceEnum("match", []string{"home", "draw", "away"}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := a.AdaptorPoint(Outcome{"cancelled"}); err == nil {
+		t.Error("AdaptorPoint() accepted an outcome that was not announced")
+	}
+	alice, _ := ecc.NewPrivateKey(big.NewInt(0xa11ce))
+	sigs := make(map[string]*ecc.ECDSAAdaptorSignature)
+	hashes := make(map[string][]byte)
+	for _, outcome := range a.Outcomes {
+		h := sha256.Sum256([]byte("CET " + outcome))
+		sig, err := a.SignCET(alice, Outcome{outcome}, h[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs[outcome], hashes[outcome] = sig, h[:]
+	}
+	att, err := o.Attest("match", "draw")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the secret is the discrete log of the adaptor point.
+	secret, err := a.Secret(att, Outcome{"draw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	point, err := a.AdaptorPoint(Outcome{"draw"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := ecc.ScalarBaseMul(secret).ToECDSA(); want.X.Cmp(point.X) != 0 || want.Y.Cmp(point.Y) != 0 {
+		t.Error("the attestation does not reveal the discrete log of the adaptor point")
+	}
+
+	pub, _ := ecc.PublicKeyPoint(alice.Public().(*ecc.PublicKey))
+	sig, err := a.CompleteCET(att, Outcome{"draw"}, sigs["draw"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pub.Verify(new(big.Int).SetBytes(hashes["draw"]), *sig); err != nil || !ok {
+		t.Error("completed signature does not verify")
+	}
+	if _, err := a.CompleteCET(att, Outcome{"home"}, sigs["home"]); !errors.Is(err, ErrOutcomeMismatch) {
+		t.Errorf("CompleteCET(home) error = %v, want %v", err, ErrOutcomeMismatch)
+	}
+
+	// an attestation that does not verify is never used.
+	relabelled := *att
+	relabelled.Outcomes = []string{"home"}
+	// the same event announced again with fresh nonces.
+	again, err := newTestOracle(t).AnnounceEnum("match", a.Outcomes, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		announcement *Announcement
+		att          *Attestation
+	}{
+		{"relabelled", a, &relabelled},
+		{"no signatures", a, &Attestation{EventID: att.EventID, Outcomes: att.Outcomes}},
+		{"other event", a, &Attestation{EventID: "other", Outcomes: att.Outcomes, Signatures: att.Signatures}},
+		{"other nonces", again, att},
+	}
+	for _, tt := range tests {
+		if _, err := tt.announcement.CompleteCET(tt.att, tt.att.Outcomes, sigs["home"]); !errors.Is(err, ErrInvalidAttestation) {
+			t.Errorf("CompleteCET() with %s error = %v, want %v", tt.name, err, ErrInvalidAttestation)
+		}
+	}
+}
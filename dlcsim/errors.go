@@ -0,0 +1,16 @@
+package dlcsim
+
+import "golang.org/x/xerrors"
+
+var (
+	// ErrAlreadyAttested means an oracle was asked to attest an event a
+	// second time. Its nonces are gone after the first attestation, because
+	// signing two outcomes with one nonce reveals the oracle key.
+	ErrAlreadyAttested = xerrors.New("event is already attested")
+	// ErrOutcomeMismatch means an attestation is for an outcome other than
+	// the one a CET pays out for.
+	ErrOutcomeMismatch = xerrors.New("attestation does not match the outcome")
+	// ErrInvalidAttestation means an attestation does not verify against
+	// the announcement it is used with.
+	ErrInvalidAttestation = xerrors.New("invalid attestation")
+)
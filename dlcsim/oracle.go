@@ -0,0 +1,243 @@
+// Package dlcsim simulates the oracle attestations of Discreet Log
+// Contracts and the adaptor signatures that make contract execution
+// transactions (CETs) valid only once the oracle attests to their outcome.
+// Building the CETs themselves is left to the caller, who passes in their
+// sighashes.
+//
+// It is a local simulation, not an implementation of the DLC
+// specification. Announcements and attestations are plain Go values with
+// no dlcspecs TLV serialization, announcements are not signed, and nothing
+// here is tested against the dlcspecs vectors, so its messages do not
+// interoperate with other DLC software. Only the ECDSA adaptor signatures
+// of package ecc follow the specification.
+package dlcsim
+
+import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"io"
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/YusukeShimizu/c-go-bitcoin/ecc"
+	"golang.org/x/xerrors"
+)
+
+const tagAttestation = "DLC/oracle/attestation/v0"
+
+// Announcement is what an oracle publishes before an event: its public key
+// and a nonce point for each signature it will make. An enumerated event
+// has one nonce and lists its Outcomes. A numeric event has one nonce per
+// digit of the value in Base, most significant first, and no Outcomes.
+type Announcement struct {
+	EventID   string
+	PublicKey *ecc.PublicKey
+	Nonces    []*ecc.PublicKey
+	Outcomes  []string
+	Base      int
+}
+
+// Attestation is the oracle's signatures of the outcome of an event, one
+// for each nonce of its announcement. For a numeric event Outcomes holds
+// the digits of the value in decimal, "0" to "9" and beyond for bases over
+// ten.
+type Attestation struct {
+	EventID    string
+	Outcomes   []string
+	Signatures []*ecc.SchnorrSignature
+}
+
+// Oracle is a local oracle that keeps its nonces in memory. It stands in
+// for a real oracle service in tests and simulations.
+type Oracle struct {
+	mu     sync.Mutex
+	key    *ecc.PrivateKey
+	events map[string]*oracleEvent
+}
+
+type oracleEvent struct {
+	announcement *Announcement
+	// nonces is nil once the event is attested.
+	nonces []*ecc.PrivateKey
+}
+
+// NewOracle returns an oracle that signs with key.
+func NewOracle(key *ecc.PrivateKey) *Oracle {
+	return &Oracle{key: key, events: make(map[string]*oracleEvent)}
+}
+
+// AnnounceEnum announces an event with the given possible outcomes. Nonces
+// are read from rand, which defaults to crypto/rand.
+func (o *Oracle) AnnounceEnum(eventID string, outcomes []string, rand io.Reader) (*Announcement, error) {
+	if len(outcomes) == 0 {
+		return nil, xerrors.New("event has no outcomes")
+	}
+	a := &Announcement{EventID: eventID, Outcomes: append([]string{}, outcomes...)}
+	return o.announce(a, 1, rand)
+}
+
+// AnnounceNumeric announces an event whose outcome is a value in
+// [0, base**digits), attested one digit at a time.
+func (o *Oracle) AnnounceNumeric(eventID string, base, digits int, rand io.Reader) (*Announcement, error) {
+	if _, err := maxValue(base, digits); err != nil {
+		return nil, err
+	}
+	return o.announce(&Announcement{EventID: eventID, Base: base}, digits, rand)
+}
+
+func (o *Oracle) announce(a *Announcement, n int, rand io.Reader) (*Announcement, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if _, ok := o.events[a.EventID]; ok {
+		return nil, xerrors.Errorf("event %q is already announced", a.EventID)
+	}
+	e := &oracleEvent{announcement: a, nonces: make([]*ecc.PrivateKey, n)}
+	for i := range e.nonces {
+		nonce, err := randomKey(rand)
+		if err != nil {
+			return nil, err
+		}
+		e.nonces[i] = nonce
+		a.Nonces = append(a.Nonces, nonce.Public().(*ecc.PublicKey))
+	}
+	a.PublicKey = o.key.Public().(*ecc.PublicKey)
+	o.events[a.EventID] = e
+	return a, nil
+}
+
+// Attest signs the outcome of an enumerated event, which must be one of
+// the announced outcomes.
+func (o *Oracle) Attest(eventID, outcome string) (*Attestation, error) {
+	return o.attest(eventID, func(a *Announcement) ([]string, error) {
+		if a.Base != 0 {
+			return nil, xerrors.Errorf("event %q is numeric", eventID)
+		}
+		if !contains(a.Outcomes, outcome) {
+			return nil, xerrors.Errorf("%q is not an outcome of event %q", outcome, eventID)
+		}
+		return []string{outcome}, nil
+	})
+}
+
+// AttestNumeric signs each digit of value, the outcome of a numeric event.
+func (o *Oracle) AttestNumeric(eventID string, value uint64) (*Attestation, error) {
+	return o.attest(eventID, func(a *Announcement) ([]string, error) {
+		if a.Base == 0 {
+			return nil, xerrors.Errorf("event %q is not numeric", eventID)
+		}
+		return digitOutcomes(value, a.Base, len(a.Nonces))
+	})
+}
+
+func (o *Oracle) attest(eventID string, outcomes func(*Announcement) ([]string, error)) (*Attestation, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	e, ok := o.events[eventID]
+	if !ok {
+		return nil, xerrors.Errorf("event %q is not announced", eventID)
+	}
+	if e.nonces == nil {
+		return nil, xerrors.Errorf("event %q: %w", eventID, ErrAlreadyAttested)
+	}
+	out, err := outcomes(e.announcement)
+	if err != nil {
+		return nil, err
+	}
+	nonces := e.nonces
+	e.nonces = nil
+	att := &Attestation{EventID: eventID, Outcomes: out}
+	for i, outcome := range out {
+		sig, err := o.key.SignSchnorrWithNonce(attestationMessage(outcome), nonces[i])
+		if err != nil {
+			return nil, err
+		}
+		att.Signatures = append(att.Signatures, sig)
+	}
+	return att, nil
+}
+
+// Verify checks that att is a valid attestation of the event of a: one
+// signature per nonce, each made with that nonce, of an outcome that the
+// event allows.
+func (a *Announcement) Verify(att *Attestation) error {
+	if att.EventID != a.EventID {
+		return xerrors.Errorf("attestation is for event %q, not %q", att.EventID, a.EventID)
+	}
+	if len(att.Outcomes) != len(a.Nonces) || len(att.Signatures) != len(a.Nonces) {
+		return xerrors.Errorf("attestation has %d outcomes and %d signatures for %d nonces", len(att.Outcomes), len(att.Signatures), len(a.Nonces))
+	}
+	pub, err := ecc.PublicKeyPoint(a.PublicKey)
+	if err != nil {
+		return xerrors.Errorf("oracle key: %w", err)
+	}
+	for i, outcome := range att.Outcomes {
+		if err := a.checkOutcome(i, outcome); err != nil {
+			return err
+		}
+		nonce, err := ecc.PublicKeyPoint(a.Nonces[i])
+		if err != nil {
+			return xerrors.Errorf("nonce %d: %w", i, err)
+		}
+		sig := att.Signatures[i]
+		if !bytes.Equal(sig.Bytes()[:32], nonce.XOnly()) {
+			return xerrors.Errorf("signature %d does not use the announced nonce", i)
+		}
+		if ok, err := pub.VerifySchnorr(attestationMessage(outcome), sig); err != nil || !ok {
+			return xerrors.Errorf("signature %d of %q is invalid", i, outcome)
+		}
+	}
+	return nil
+}
+
+// checkOutcome reports whether outcome can be attested with nonce i.
+func (a *Announcement) checkOutcome(i int, outcome string) error {
+	if a.Base == 0 {
+		if !contains(a.Outcomes, outcome) {
+			return xerrors.Errorf("%q is not an outcome of event %q", outcome, a.EventID)
+		}
+		return nil
+	}
+	if d, err := strconv.Atoi(outcome); err != nil || d < 0 || d >= a.Base || strconv.Itoa(d) != outcome {
+		return xerrors.Errorf("digit %d is %q, not a digit in base %d", i, outcome, a.Base)
+	}
+	return nil
+}
+
+// attestationMessage returns the 32-byte message an oracle signs for an
+// outcome, hash_DLC/oracle/attestation/v0(outcome).
+func attestationMessage(outcome string) []byte {
+	tag := sha256.Sum256([]byte(tagAttestation))
+	h := sha256.New()
+	h.Write(tag[:])
+	h.Write(tag[:])
+	h.Write([]byte(outcome))
+	return h.Sum(nil)
+}
+
+// randomKey returns a private key with a secret read from rand.
+func randomKey(rand io.Reader) (*ecc.PrivateKey, error) {
+	var b [32]byte
+	for {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return nil, xerrors.Errorf("reading randomness: %w", err)
+		}
+		// out of range secrets are rare enough to just try again.
+		if key, err := ecc.NewPrivateKey(new(big.Int).SetBytes(b[:])); err == nil {
+			return key, nil
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
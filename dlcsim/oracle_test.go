@@ -0,0 +1,101 @@
+package dlcsim
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/YusukeShimizu/c-go-bitcoin/ecc"
+)
+
+func newTestOracle(t *testing.T) *Oracle {
+	t.Helper()
+	key, err := ecc.NewPrivateKey(big.NewInt(0x0dead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewOracle(key)
+}
+
+func TestOracleEnum(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	o := newTestOracle(t)
+	a, err := o.AnnounceEnum("election", []string{"yes", "no"}, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.AnnounceEnum("election", []string{"yes", "no"}, r); err == nil {
+		t.Error("announced the same event twice")
+	}
+	if _, err := o.Attest("election", "maybe"); err == nil {
+		t.Error("attested an outcome that was not announced")
+	}
+	if _, err := o.AttestNumeric("election", 1); err == nil {
+		t.Error("attested a number for an enumerated event")
+	}
+	att, err := o.Attest("election", "yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Verify(att); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+	if _, err := o.Attest("election", "no"); !errors.Is(err, ErrAlreadyAttested) {
+		t.Errorf("second Attest() error = %v, want %v", err, ErrAlreadyAttested)
+	}
+
+	// the signature of "yes" does not attest "no".
+	forged := *att
+	forged.Outcomes = []string{"no"}
+	if err := a.Verify(&forged); err == nil {
+		t.Error("Verify() accepted a changed outcome")
+	}
+	forged = *att
+	forged.EventID = "other"
+	if err := a.Verify(&forged); err == nil {
+		t.Error("Verify() accepted an attestation of another event")
+	}
+}
+
+func TestOracleNumeric(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	o := newTestOracle(t)
+	a, err := o.AnnounceNumeric("price", 10, 5, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Nonces) != 5 {
+		t.Fatalf("announced %d nonces, want 5", len(a.Nonces))
+	}
+	if _, err := o.AttestNumeric("price", 100000); err == nil {
+		t.Error("attested a value with too many digits")
+	}
+	att, err := o.AttestNumeric("price", 4207)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"0", "4", "2", "0", "7"}
+	for i := range want {
+		if att.Outcomes[i] != want[i] {
+			t.Fatalf("attested digits %v, want %v", att.Outcomes, want)
+		}
+	}
+	if err := a.Verify(att); err != nil {
+		t.Errorf("Verify() = %v", err)
+	}
+
+	// the signatures of two digits swapped use the wrong nonces.
+	forged := *att
+	forged.Outcomes = []string{"0", "4", "0", "2", "7"}
+	forged.Signatures = []*ecc.SchnorrSignature{att.Signatures[0], att.Signatures[1], att.Signatures[3], att.Signatures[2], att.Signatures[4]}
+	if err := a.Verify(&forged); err == nil {
+		t.Error("Verify() accepted swapped digits")
+	}
+
+	for _, tt := range []struct{ base, digits int }{{1, 4}, {10, 0}, {2, 64}, {10, 20}} {
+		if _, err := o.AnnounceNumeric("bad", tt.base, tt.digits, r); err == nil {
+			t.Errorf("AnnounceNumeric(base %d, %d digits) succeeded", tt.base, tt.digits)
+		}
+	}
+}
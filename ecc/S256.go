@@ -1,11 +1,11 @@
 package ecc
 
 import (
-	"bytes"
 	"crypto/sha256"
 	"fmt"
 	"hash"
 	"math/big"
+	"sync"
 
 	"github.com/btcsuite/btcutil/base58"
 	"golang.org/x/crypto/ripemd160"
@@ -13,27 +13,31 @@ import (
 )
 
 // 2**256 - 2**32 - 977
+// The returned value is shared and must not be modified.
 func genPrime() *big.Int {
-	prime := big.NewInt(0)
-	prime.Exp(big.NewInt(2), big.NewInt(256), nil)
-	fraction := big.NewInt(0)
-	fraction.Exp(big.NewInt(2), big.NewInt(32), nil)
-	fraction.Add(fraction, big.NewInt(977))
-	return prime.Sub(prime, fraction)
+	return Secp256k1().P
 }
 
+// We specify the order of the group generated by G, n.
+// The returned value is shared and must not be modified.
+func genN() *big.Int {
+	return Secp256k1().N
+}
+
+var (
+	s256GOnce sync.Once
+	s256G     *s256Point
+	s256GErr  error
+)
+
+// genG returns the generator of secp256k1. Points are immutable, so every
+// caller shares the same value.
 func genG() (*s256Point, error) {
-	gxhex := "0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
-	gx, ok := new(big.Int).SetString(gxhex, 0)
-	if !ok {
-		return nil, xerrors.Errorf("coundn't generate x of the generator point G from hex:,%s", gxhex)
-	}
-	gyhex := "0x483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
-	gy, ok := new(big.Int).SetString(gyhex, 0)
-	if !ok {
-		return nil, xerrors.Errorf("coundn't generate y of the generator point G from hex:,%s", gxhex)
-	}
-	return NewS256Point(gx, gy)
+	s256GOnce.Do(func() {
+		g := Secp256k1().G
+		s256G, s256GErr = NewS256Point(g.x.number, g.y.number)
+	})
+	return s256G, s256GErr
 }
 
 func NewS256Field(number *big.Int) (*fieldElement, error) {
@@ -45,95 +49,143 @@ func NewS256Field(number *big.Int) (*fieldElement, error) {
 }
 
 type s256Point struct {
-	*point
+	// nil means infinity.
+	x *s256FieldElement
+	y *s256FieldElement
 	n *big.Int
 }
 
+// s256B is the curve coefficient b of secp256k1. The s256Point arithmetic
+// is specialised for its a = 0.
+var s256B = mustS256FieldFromHex(Secp256k1().B.Text(16))
+
 func NewS256Point(bx, by *big.Int) (*s256Point, error) {
-	prime := genPrime()
-	x, err := NewFieldElement(bx, prime)
-	if err != nil {
-		return nil, err
+	x := new(s256FieldElement)
+	if x.SetBig(bx) {
+		return nil, xerrors.New("number is larger than prime")
 	}
-	y, err := NewFieldElement(by, prime)
-	if err != nil {
-		return nil, err
+	y := new(s256FieldElement)
+	if y.SetBig(by) {
+		return nil, xerrors.New("number is larger than prime")
 	}
-	a, err := NewFieldElement(big.NewInt(0), prime)
-	if err != nil {
-		return nil, err
+	p := &s256Point{x, y, genN()}
+	if !p.onCurve() {
+		return nil, xerrors.Errorf("(%v, %v) is not on the curve", bx, by)
 	}
-	b, err := NewFieldElement(big.NewInt(7), prime)
-	if err != nil {
-		return nil, err
+	return p, nil
+}
+
+// onCurve reports whether s is a finite point satisfying y2 = x3 + 7.
+func (s *s256Point) onCurve() bool {
+	if s.IsInfinity() || s.y == nil {
+		return false
+	}
+	left := new(s256FieldElement).Square(s.y)
+	right := new(s256FieldElement).Square(s.x)
+	right.Mul(right, s.x).Add(right, s256B)
+	return left.Equal(right)
+}
+
+// S256Infinity returns the point at infinity on secp256k1.
+func S256Infinity() *s256Point {
+	return &s256Point{nil, nil, genN()}
+}
+
+// IsInfinity reports whether s is the point at infinity.
+func (s *s256Point) IsInfinity() bool {
+	return s.x == nil
+}
+
+func (s *s256Point) Eq(other *s256Point) bool {
+	if other == nil {
+		return false
 	}
-	sp, err := NewPoint(x, y, a, b)
-	if err != nil {
-		return nil, err
+	if s.IsInfinity() || other.IsInfinity() {
+		return s.IsInfinity() && other.IsInfinity()
 	}
-	hexN := "0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"
-	// We specify the order of the group generated by G, n.s
-	n, ok := new(big.Int).SetString(hexN, 0)
-	if !ok {
-		return nil, xerrors.Errorf("coundn't generate the order of the group generated by G from hex:,%s", hexN)
+	return s.x.Equal(other.x) && s.y.Equal(other.y)
+}
+
+// newS256PointFromJacobian converts j to a fresh affine point.
+func newS256PointFromJacobian(j *s256JacobianPoint) *s256Point {
+	x, y := j.toAffine()
+	return &s256Point{x, y, genN()}
+}
+
+// Add returns s + other as a new point. Neither s nor other is modified.
+func (s *s256Point) Add(other *s256Point) *s256Point {
+	var j s256JacobianPoint
+	j.setAffine(s).addMixed(&j, other)
+	return newS256PointFromJacobian(&j)
+}
+
+// FastRMul returns coefficient * s using double-and-add. Neither s nor
+// coefficient is modified.
+func (s *s256Point) FastRMul(coefficient *big.Int) *s256Point {
+	var result s256JacobianPoint
+	result.setInfinity()
+	for i := coefficient.BitLen() - 1; i >= 0; i-- {
+		result.double(&result)
+		if coefficient.Bit(i) == 1 {
+			result.addMixed(&result, s)
+		}
 	}
-	return &s256Point{sp, n}, nil
+	return newS256PointFromJacobian(&result)
 }
 
-func (s s256Point) SRMul(coefficient *big.Int) error {
-	return s.FastRMul(coefficient.Mod(coefficient, s.n))
+// SRMul returns coefficient * s after reducing coefficient modulo n. It uses
+// the GLV endomorphism and runs in variable time, and always agrees with
+// FastRMul.
+func (s *s256Point) SRMul(coefficient *big.Int) *s256Point {
+	var k Scalar
+	k.SetBig(coefficient)
+	return s.glvMul(&k)
 }
 
-func (s s256Point) Verify(z *big.Int, sig Signature) (bool, error) {
-	// s_inv = pow(sig.s, N - 2, N)
-	s_inv := big.NewInt(0).Exp(sig.s, big.NewInt(0).Sub(s.n, big.NewInt(2)), s.n)
-	// u = z * s_inv % N
-	u := big.NewInt(0).Mul(z, s_inv)
-	u = u.Mod(u, s.n)
-	// v = sig.r * s_inv % N
-	v := big.NewInt(0).Mul(sig.r, s_inv)
-	v = v.Mod(v, s.n)
+// Verify reports whether sig is a valid signature of z by s. Signatures
+// whose r or s is not in [1, n-1] are rejected.
+func (s *s256Point) Verify(z *big.Int, sig Signature) (bool, error) {
+	if s.IsInfinity() {
+		return false, xerrors.New("public key is infinity")
+	}
+	var r, sigS, e Scalar
+	if r.SetBig(sig.r) || r.IsZero() || sigS.SetBig(sig.s) || sigS.IsZero() {
+		return false, nil
+	}
+	e.SetBig(z)
+	// s_inv = sig.s**-1
+	sInv := new(Scalar).inverseVar(&sigS)
+	// u = z * s_inv, v = sig.r * s_inv
+	u := new(Scalar).Mul(&e, sInv)
+	v := new(Scalar).Mul(&r, sInv)
 	// total = u * G + v * self
-	g, err := genG()
-	if err != nil {
-		return false, err
-	}
-	// u * G
-	err = g.SRMul(u)
-	if err != nil {
-		return false, err
-	}
-	sCopy, err := NewS256Point(s.x.number, s.y.number)
-	if err != nil {
-		return false, err
+	total := DoubleScalarMul(u.Big(), v.Big(), s)
+	if total.IsInfinity() {
+		return false, nil
 	}
-	// v * self
-	err = sCopy.SRMul(v)
-	if err != nil {
-		return false, err
-	}
-	err = g.Add(sCopy.point)
-	if err != nil {
-		return false, err
-	}
-	return g.x.number.Cmp(sig.r) == 0, nil
+	var x Scalar
+	x.SetBig(total.x.Big())
+	return x.Equal(&r), nil
 }
 
-// returns the binary version of the SEC format
-func (s s256Point) Sec(compressed bool) (b []byte) {
-	x := s.x.number.Bytes()
-	y := s.y.number.Bytes()
-
-	/* Pad X and Y coordinate bytes to 32-bytes */
-	padded_x := append(bytes.Repeat([]byte{0x00}, 32-len(x)), x...)
-	padded_y := append(bytes.Repeat([]byte{0x00}, 32-len(y)), y...)
+// returns the binary version of the SEC format. The point at infinity is
+// the single byte 0x00, as in SEC 1, which ParseSec does not accept.
+func (s *s256Point) Sec(compressed bool) (b []byte) {
+	if s.IsInfinity() {
+		return []byte{0x00}
+	}
+	/* X and Y coordinate bytes are always 32-bytes */
+	x := s.x.Bytes()
+	y := s.y.Bytes()
+	padded_x := x[:]
+	padded_y := y[:]
 
 	if !compressed {
 		/* Add prefix 0x04 for uncompressed coordinates */
 		return append([]byte{0x04}, append(padded_x, padded_y...)...)
 	}
 	// if y is even
-	if s.y.number.Bit(0) == 0 {
+	if !s.y.IsOdd() {
 		return append([]byte{0x02}, padded_x...)
 	}
 	return append([]byte{0x03}, padded_x...)
@@ -158,98 +210,69 @@ func Hash256(buf []byte) []byte {
 	return calcHash(calcHash(buf, sha256.New()), sha256.New())
 }
 
-func (s s256Point) Addresses(compressed, testnet bool) string {
+// Addresses returns the P2PKH address of s.
+func (s *s256Point) Addresses(compressed, testnet bool) string {
 	h160 := Hash160(s.Sec(compressed))
-	var prefix []byte
 	if testnet {
-		prefix = []byte{0x6f}
-	} else {
-		prefix = []byte{0x00}
+		return encodeBase58Check(0x6f, h160)
 	}
-	h160WithPrefix := append(prefix, h160...)
-	h160WithCheckSum := append(h160WithPrefix, Hash256(h160WithPrefix)[0:4]...)
-	return base58.Encode(h160WithCheckSum)
+	return encodeBase58Check(0x00, h160)
 }
 
+// encodeBase58Check returns the Base58Check encoding of version || payload.
+func encodeBase58Check(version byte, payload []byte) string {
+	b := append([]byte{version}, payload...)
+	b = append(b, Hash256(b)[0:4]...)
+	return base58.Encode(b)
+}
+
+// ParseSec decodes a compressed or uncompressed SEC encoded public key. The
+// returned errors wrap ErrInvalidPubKeyLength, ErrInvalidPubKeyPrefix or
+// ErrPubKeyNotOnCurve.
 func ParseSec(bin []byte) (*s256Point, error) {
-	// obtain marker
-	format := bin[0]
-	// uncompressed
-	if format == byte(0x4) {
+	if len(bin) == 0 {
+		return nil, xerrors.Errorf("empty SEC encoding: %w", ErrInvalidPubKeyLength)
+	}
+	switch bin[0] {
+	case 0x04:
+		if len(bin) != 65 {
+			return nil, xerrors.Errorf("uncompressed SEC encoding must be 65 bytes, got %d: %w", len(bin), ErrInvalidPubKeyLength)
+		}
 		x := new(big.Int).SetBytes(bin[1:33])
 		y := new(big.Int).SetBytes(bin[33:])
-		return NewS256Point(x, y)
-	}
-	x, err := NewFieldElement(new(big.Int).SetBytes(bin[1:]), genPrime())
-	if err != nil {
-		return nil, err
-	}
-	right, err := NewFieldElement(big.NewInt(0), x.prime)
-	if err != nil {
-		return nil, err
-	}
-	right, err = right.Pow(x, big.NewInt(3))
-	if err != nil {
-		return nil, err
-	}
-	ax, err := NewFieldElement(big.NewInt(0), x.prime)
-	if err != nil {
-		return nil, err
-	}
-	a, err := NewFieldElement(big.NewInt(0), x.prime)
-	if err != nil {
-		return nil, err
-	}
-	b, err := NewFieldElement(big.NewInt(7), x.prime)
-	if err != nil {
-		return nil, err
-	}
-	axplusb, err := ax.Mul(a, x)
-	if err != nil {
-		return nil, err
-	}
-	axplusb, err = axplusb.Add(axplusb, b)
-	if err != nil {
-		return nil, err
-	}
-	right, err = right.Add(right, axplusb)
-	if err != nil {
-		return nil, err
-	}
-	// left = right**((P + 1) / 4)
-	p := big.NewInt(0).Add(genPrime(), big.NewInt(1))
-	p = p.Div(p, big.NewInt(4))
-	left, err := NewFieldElement(big.NewInt(0), x.prime)
-	if err != nil {
-		return nil, err
-	}
-	left, err = left.Pow(right, p)
-	if err != nil {
-		return nil, err
-	}
-	var (
-		evenLeft *fieldElement
-		oddLeft  *fieldElement
-	)
-	if big.NewInt(0).Mod(left.number, big.NewInt(2)).Cmp(big.NewInt(0)) == 0 {
-		evenLeft = left
-		oddLeft, err = NewS256Field(
-			big.NewInt(0).Sub(genPrime(), left.number),
-		)
+		p, err := NewS256Point(x, y)
 		if err != nil {
-			return nil, err
+			return nil, xerrors.Errorf("%v: %w", err, ErrPubKeyNotOnCurve)
 		}
-	} else {
-		oddLeft = left
-		evenLeft, err = NewS256Field(
-			big.NewInt(0).Sub(genPrime(), left.number),
-		)
-		if err != nil {
-			return nil, err
+		return p, nil
+	case 0x02, 0x03:
+		if len(bin) != 33 {
+			return nil, xerrors.Errorf("compressed SEC encoding must be 33 bytes, got %d: %w", len(bin), ErrInvalidPubKeyLength)
 		}
+	default:
+		return nil, xerrors.Errorf("prefix %#x: %w", bin[0], ErrInvalidPubKeyPrefix)
 	}
-	if format == byte(0x2) {
-		return NewS256Point(x.number, evenLeft.number)
+	var xb [32]byte
+	copy(xb[:], bin[1:])
+	x := new(s256FieldElement)
+	if x.SetBytes(&xb) {
+		return nil, xerrors.Errorf("x is larger than prime: %w", ErrPubKeyNotOnCurve)
 	}
-	return NewS256Point(x.number, oddLeft.number)
+	return decompressS256(x, bin[0] == 0x03)
+}
+
+// decompressS256 returns the point with the given x whose y has the given
+// parity.
+func decompressS256(x *s256FieldElement, odd bool) (*s256Point, error) {
+	// right = x**3 + 7
+	right := new(s256FieldElement).Square(x)
+	right.Mul(right, x).Add(right, s256B)
+	y := new(s256FieldElement)
+	if !y.Sqrt(right) {
+		return nil, xerrors.Errorf("no point with x = %v: %w", x, ErrPubKeyNotOnCurve)
+	}
+	if y.IsOdd() != odd {
+		y.Neg(y)
+	}
+	return &s256Point{new(s256FieldElement).Set(x), y, genN()}, nil
 }
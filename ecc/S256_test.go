@@ -1,7 +1,9 @@
 package ecc
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -59,6 +61,12 @@ func mustGetFromHex(hex string) *big.Int {
 	return hexb
 }
 
+func mustS256Field(number *big.Int) *s256FieldElement {
+	f := new(s256FieldElement)
+	f.SetBig(number)
+	return f
+}
+
 func TestNewS256Point(t *testing.T) {
 	type args struct {
 		x *big.Int
@@ -77,24 +85,8 @@ func TestNewS256Point(t *testing.T) {
 				y: mustGetFromHex("0x6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264da"),
 			},
 			want: &s256Point{
-				point: &point{
-					x: &fieldElement{
-						number: mustGetFromHex("0x5cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc"),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: mustGetFromHex("0x6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264da"),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(mustGetFromHex("0x5cbdf0646e5db4eaa398f365f2ea7a0e3d419b7e0330e39ce92bddedcac4f9bc")),
+				y: mustS256Field(mustGetFromHex("0x6aebca40ba255960a3178d6d861a54dba813d0b813fde7b5a5082628087264da")),
 				n: mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
 			},
 			wantErr: false,
@@ -117,8 +109,8 @@ func TestNewS256Point(t *testing.T) {
 				}
 				return
 			}
-			if !got.point.Eq(tt.want.point) {
-				t.Errorf("NewS256Point() = %v, want %v", got.point, tt.want.point)
+			if !got.Eq(tt.want) {
+				t.Errorf("NewS256Point() = %v, want %v", got, tt.want)
 			}
 			if got.n.Cmp(tt.want.n) != 0 {
 				t.Errorf("NewS256Point() generated N is wrong. %v, want %v", got.n, tt.want.n)
@@ -129,77 +121,72 @@ func TestNewS256Point(t *testing.T) {
 
 func Test_s256Point_sRMul(t *testing.T) {
 	type fields struct {
-		point *point
-		n     *big.Int
+		x *s256FieldElement
+		y *s256FieldElement
+		n *big.Int
 	}
 
 	tests := []struct {
 		name        string
 		fields      fields
 		coefficient *big.Int
-		want        *point
-		wantErr     bool
+		want        *s256Point
 	}{
 		{
 			name: "OK",
 			fields: fields{
-				point: &point{
-					x: &fieldElement{
-						number: big.NewInt(192),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: big.NewInt(105),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(big.NewInt(192)),
+				y: mustS256Field(big.NewInt(105)),
 				n: mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141"),
 			},
 			coefficient: big.NewInt(3),
-			want: &point{
-				x: &fieldElement{
-					number: mustGetFromHex("0x6cccdbe1d22d7bcc12df177da0d6e6ec4b790f5da805b983d7b1bea1da916b3b"),
-					prime:  genPrime(),
-				},
-				y: &fieldElement{
-					number: mustGetFromHex("0x26a9359a5f73ddcad408ff41ce4eb5213564ff9cfecd53877a84b0ce8d209fb9"),
-					prime:  genPrime(),
-				},
-				a: &fieldElement{
-					number: big.NewInt(0),
-					prime:  genPrime(),
-				},
-				b: &fieldElement{
-					number: big.NewInt(7),
-					prime:  genPrime(),
-				},
+			want: &s256Point{
+				x: mustS256Field(mustGetFromHex("0x6cccdbe1d22d7bcc12df177da0d6e6ec4b790f5da805b983d7b1bea1da916b3b")),
+				y: mustS256Field(mustGetFromHex("0x26a9359a5f73ddcad408ff41ce4eb5213564ff9cfecd53877a84b0ce8d209fb9")),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := s256Point{
-				point: tt.fields.point,
-				n:     tt.fields.n,
-			}
-			if err := s.SRMul(tt.coefficient); (err != nil) != tt.wantErr {
-				t.Errorf("s256Point.sRMul() error = %v, wantErr %v", err, tt.wantErr)
+			p := s256Point{
+				x: tt.fields.x,
+				y: tt.fields.y,
+				n: tt.fields.n,
 			}
+			s := p.SRMul(tt.coefficient)
 			if !s.Eq(tt.want) {
-				t.Errorf("point.SRMul() = x:%v y:%v want x:%v y:%v", s.x.number, s.y.number, tt.want.x.number, tt.want.y.number)
+				t.Errorf("point.SRMul() = x:%v y:%v want x:%v y:%v", s.x, s.y, tt.want.x, tt.want.y)
 			}
 		})
 	}
 }
 
+func Test_s256Point_doesNotAliasInputs(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	gx, gy := *g.x, *g.y
+	coefficient := big.NewInt(1485)
+	sum := g.SRMul(coefficient).Add(g)
+	if !g.x.Equal(&gx) || !g.y.Equal(&gy) {
+		t.Errorf("receiver was modified to x:%v y:%v", g.x, g.y)
+	}
+	if coefficient.Cmp(big.NewInt(1485)) != 0 {
+		t.Errorf("coefficient was modified to %v", coefficient)
+	}
+	if want := g.SRMul(big.NewInt(1486)); !sum.Eq(want) {
+		t.Errorf("1485*G+G = x:%v y:%v want x:%v y:%v", sum.x, sum.y, want.x, want.y)
+	}
+	inf := S256Infinity()
+	if !inf.Add(g).Eq(g) || !g.Add(inf).Eq(g) {
+		t.Errorf("G + infinity should be G")
+	}
+	if !inf.Eq(S256Infinity()) || inf.Eq(g) || g.Eq(inf) {
+		t.Errorf("infinity should only equal itself")
+	}
+}
+
 func Test_genG(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -209,24 +196,8 @@ func Test_genG(t *testing.T) {
 		{
 			name: "OK",
 			want: &s256Point{
-				point: &point{
-					x: &fieldElement{
-						number: mustGetFromHex("0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: mustGetFromHex("0x483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(mustGetFromHex("0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")),
+				y: mustS256Field(mustGetFromHex("0x483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")),
 				n: nil,
 			},
 		},
@@ -238,12 +209,11 @@ func Test_genG(t *testing.T) {
 				t.Errorf("genG() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !got.Eq(tt.want.point) {
-				t.Errorf("point.SRMul() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, tt.want.x.number, tt.want.y.number)
+			if !got.Eq(tt.want) {
+				t.Errorf("point.SRMul() = x:%v y:%v want x:%v y:%v", got.x, got.y, tt.want.x, tt.want.y)
 			}
-			_ = got.SRMul(got.n)
-			if got.x != nil || got.y != nil {
-				t.Errorf("got should be infinity but got x:%v, y:%v", got.x, got.y)
+			if inf := got.SRMul(got.n); !inf.IsInfinity() {
+				t.Errorf("got should be infinity but got x:%v, y:%v", inf.x, inf.y)
 			}
 
 		})
@@ -286,12 +256,9 @@ func Test_Pubpoint(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			err = g.SRMul(tt.secret)
-			if err != nil {
-				t.Fatal(err)
-			}
-			if !s.Eq(g.point) {
-				t.Errorf("x:%v y:%v want x:%v y:%v", g.x.number, g.y.number, s.x.number, s.y.number)
+			g = g.SRMul(tt.secret)
+			if !s.Eq(g) {
+				t.Errorf("x:%v y:%v want x:%v y:%v", g.x, g.y, s.x, s.y)
 			}
 		})
 	}
@@ -399,9 +366,7 @@ func Test_s256Point_Sec(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			if err := g.SRMul(tt.coefficient); err != nil {
-				t.Fatal(err)
-			}
+			g = g.SRMul(tt.coefficient)
 			if gotB := g.Sec(tt.compressed); !reflect.DeepEqual(gotB, tt.wantB) {
 				t.Errorf("s256Point.Sec() = %v, want %v", gotB, tt.wantB)
 			}
@@ -409,6 +374,18 @@ func Test_s256Point_Sec(t *testing.T) {
 	}
 }
 
+func Test_s256Point_Sec_infinity(t *testing.T) {
+	inf := S256Infinity()
+	for _, compressed := range []bool{false, true} {
+		if got := inf.Sec(compressed); !bytes.Equal(got, []byte{0x00}) {
+			t.Errorf("Sec(%v) = %x, want 00", compressed, got)
+		}
+	}
+	if _, err := ParseSec(inf.Sec(true)); !errors.Is(err, ErrInvalidPubKeyPrefix) {
+		t.Errorf("ParseSec(00) error = %v, want %v", err, ErrInvalidPubKeyPrefix)
+	}
+}
+
 func Test_s256Point_Addresses(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -452,9 +429,7 @@ func Test_s256Point_Addresses(t *testing.T) {
 			if err != nil {
 				t.Fatal(err)
 			}
-			if err := g.SRMul(tt.coefficient); err != nil {
-				t.Fatal(err)
-			}
+			g = g.SRMul(tt.coefficient)
 			if got := g.Addresses(tt.compressed, tt.testnet); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("s256Point.Sec() = %v, want %v", got, tt.want)
 			}
@@ -473,24 +448,8 @@ func Test_parse(t *testing.T) {
 			name: "OK",
 			in:   mustDecodeString("04aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3"),
 			want: &s256Point{
-				point: &point{
-					x: &fieldElement{
-						number: mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: mustGetFromHex("0x21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3"),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e")),
+				y: mustS256Field(mustGetFromHex("0x21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3")),
 			},
 			wantErr: false,
 		},
@@ -498,24 +457,8 @@ func Test_parse(t *testing.T) {
 			name: "OK if compressed",
 			in:   mustDecodeString("03aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"),
 			want: &s256Point{
-				point: &point{
-					x: &fieldElement{
-						number: mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: mustGetFromHex("0x21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3"),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e")),
+				y: mustS256Field(mustGetFromHex("0x21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3")),
 			},
 			wantErr: false,
 		},
@@ -523,24 +466,8 @@ func Test_parse(t *testing.T) {
 			name: "OK if compressed with odd",
 			in:   mustDecodeString("02aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"),
 			want: &s256Point{
-				point: &point{
-					x: &fieldElement{
-						number: mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"),
-						prime:  genPrime(),
-					},
-					y: &fieldElement{
-						number: mustGetFromHex("0xde13ac0bf1053b853e3adee4dedcad81f164a812186f3b25e18d36df4825a78c"),
-						prime:  genPrime(),
-					},
-					a: &fieldElement{
-						number: big.NewInt(0),
-						prime:  genPrime(),
-					},
-					b: &fieldElement{
-						number: big.NewInt(7),
-						prime:  genPrime(),
-					},
-				},
+				x: mustS256Field(mustGetFromHex("0xaee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e")),
+				y: mustS256Field(mustGetFromHex("0xde13ac0bf1053b853e3adee4dedcad81f164a812186f3b25e18d36df4825a78c")),
 			},
 			wantErr: false,
 		},
@@ -552,9 +479,62 @@ func Test_parse(t *testing.T) {
 				t.Errorf("parse() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !got.point.Eq(tt.want.point) {
-				t.Errorf("parse() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, tt.want.x.number, tt.want.y.number)
+			if !got.Eq(tt.want) {
+				t.Errorf("parse() = x:%v y:%v want x:%v y:%v", got.x, got.y, tt.want.x, tt.want.y)
+			}
+		})
+	}
+}
+
+func TestParseSec_errors(t *testing.T) {
+	compressed := mustDecodeString("03aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e")
+	uncompressed := mustDecodeString("04aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3")
+	offCurve := append([]byte{}, uncompressed...)
+	offCurve[64] ^= 1
+	tests := []struct {
+		name    string
+		in      []byte
+		wantErr error
+	}{
+		{name: "NG if empty", in: nil, wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if only a prefix", in: []byte{0x02}, wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if compressed is short", in: compressed[:32], wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if compressed is long", in: append(append([]byte{}, compressed...), 0x00), wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if uncompressed is short", in: uncompressed[:64], wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if uncompressed has a compressed length", in: append([]byte{0x04}, compressed[1:]...), wantErr: ErrInvalidPubKeyLength},
+		{name: "NG if prefix is zero", in: append([]byte{0x00}, compressed[1:]...), wantErr: ErrInvalidPubKeyPrefix},
+		{name: "NG if prefix is hybrid", in: append([]byte{0x06}, uncompressed[1:]...), wantErr: ErrInvalidPubKeyPrefix},
+		{name: "NG if not on the curve", in: offCurve, wantErr: ErrPubKeyNotOnCurve},
+		{name: "NG if x is the prime", in: append([]byte{0x02}, genPrime().Bytes()...), wantErr: ErrPubKeyNotOnCurve},
+		// x = 5 gives x**3 + 7 = 132, which is not a square modulo p.
+		{name: "NG if x has no point", in: append([]byte{0x02}, mustDecodeString("0000000000000000000000000000000000000000000000000000000000000005")...), wantErr: ErrPubKeyNotOnCurve},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSec(tt.in)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseSec() error = %v, want %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func FuzzParseSec(f *testing.F) {
+	f.Add(mustDecodeString("03aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"))
+	f.Add(mustDecodeString("02aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e"))
+	f.Add(mustDecodeString("04aee2e7d843f7430097859e2bc603abcc3274ff8169c1a469fee0f20614066f8e21ec53f40efac47ac1c5211b2123527e0e9b57ede790c4da1e72c91fb7da54a3"))
+	f.Add([]byte{})
+	f.Add([]byte{0x04})
+	f.Fuzz(func(t *testing.T, in []byte) {
+		p, err := ParseSec(in)
+		if err != nil {
+			if p != nil {
+				t.Errorf("ParseSec(%x) returned a point with error %v", in, err)
+			}
+			return
+		}
+		if got := p.Sec(in[0] != 0x04); !bytes.Equal(got, in) {
+			t.Errorf("ParseSec(%x).Sec() = %x", in, got)
+		}
+	})
+}
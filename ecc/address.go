@@ -0,0 +1,229 @@
+package ecc
+
+import (
+	"strings"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/btcsuite/btcutil/bech32"
+	"golang.org/x/xerrors"
+)
+
+// P2SHP2WPKHAddress returns the BIP49 address that nests the P2WPKH program
+// of s in P2SH. Segwit keys are always compressed.
+func (s *s256Point) P2SHP2WPKHAddress(testnet bool) string {
+	h160 := Hash160(p2wpkhScript(Hash160(s.Sec(true))))
+	if testnet {
+		return encodeBase58Check(0xc4, h160)
+	}
+	return encodeBase58Check(0x05, h160)
+}
+
+// P2WPKHAddress returns the native segwit v0 address of s.
+func (s *s256Point) P2WPKHAddress(testnet bool) string {
+	addr, err := encodeSegwitAddress(segwitHRP(testnet), 0, Hash160(s.Sec(true)))
+	if err != nil {
+		// a 20-byte version 0 program always encodes.
+		panic(err)
+	}
+	return addr
+}
+
+// P2TRAddress returns the BIP86 taproot address of s, which commits to no
+// script tree and so can only be spent with the tweaked key.
+func (s *s256Point) P2TRAddress(testnet bool) string {
+	q, _, err := s.TapTweak(nil)
+	if err != nil {
+		panic(err)
+	}
+	addr, err := encodeSegwitAddress(segwitHRP(testnet), 1, q.XOnly())
+	if err != nil {
+		// a 32-byte version 1 program always encodes.
+		panic(err)
+	}
+	return addr
+}
+
+// p2pkhScript returns OP_DUP OP_HASH160 <20-byte hash> OP_EQUALVERIFY
+// OP_CHECKSIG.
+func p2pkhScript(h160 []byte) []byte {
+	script := append([]byte{0x76, 0xa9, 0x14}, h160...)
+	return append(script, 0x88, 0xac)
+}
+
+// p2shScript returns OP_HASH160 <20-byte hash> OP_EQUAL.
+func p2shScript(h160 []byte) []byte {
+	script := append([]byte{0xa9, 0x14}, h160...)
+	return append(script, 0x87)
+}
+
+// p2wpkhScript returns the witness program script OP_0 <20-byte hash>.
+func p2wpkhScript(h160 []byte) []byte {
+	return append([]byte{0x00, 0x14}, h160...)
+}
+
+// p2trScript returns the witness program script OP_1 <32-byte x-only key>.
+func p2trScript(xonly []byte) []byte {
+	return append([]byte{0x51, 0x20}, xonly...)
+}
+
+func segwitHRP(testnet bool) string {
+	if testnet {
+		return "tb"
+	}
+	return "bc"
+}
+
+// encodeSegwitAddress returns the address of a witness program: BIP173
+// bech32 for version 0 and BIP350 bech32m for later versions.
+func encodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	if version > 16 {
+		return "", xerrors.Errorf("invalid witness version %d", version)
+	}
+	data, err := bech32.ConvertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data = append([]byte{version}, data...)
+	if version == 0 {
+		return bech32.Encode(hrp, data)
+	}
+	return encodeBech32m(hrp, data), nil
+}
+
+// addressScript returns the output script that address pays to. It knows
+// mainnet, testnet and regtest P2PKH, P2SH, segwit v0 and P2TR addresses.
+func addressScript(address string) ([]byte, error) {
+	if payload, version, err := base58.CheckDecode(address); err == nil {
+		if len(payload) != 20 {
+			return nil, xerrors.Errorf("base58 address %s has a %d-byte payload", address, len(payload))
+		}
+		switch version {
+		case 0x00, 0x6f:
+			return p2pkhScript(payload), nil
+		case 0x05, 0xc4:
+			return p2shScript(payload), nil
+		}
+		return nil, xerrors.Errorf("unknown base58 address version %#x", version)
+	}
+	hrp, data, isBech32m, err := decodeBech32(address)
+	if err != nil {
+		return nil, xerrors.Errorf("%s is neither a base58 nor a bech32 address: %w", address, err)
+	}
+	switch hrp {
+	case "bc", "tb", "bcrt":
+	default:
+		return nil, xerrors.Errorf("unknown address prefix %s", hrp)
+	}
+	if len(data) == 0 {
+		return nil, xerrors.New("empty witness program")
+	}
+	version := data[0]
+	program, err := bech32.ConvertBits(data[1:], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	// BIP350: version 0 uses bech32, every later version bech32m.
+	if isBech32m != (version != 0) {
+		return nil, xerrors.Errorf("witness version %d with the wrong checksum", version)
+	}
+	switch version {
+	case 0:
+		if len(program) != 20 && len(program) != 32 {
+			return nil, xerrors.Errorf("witness v0 program must be 20 or 32 bytes, got %d", len(program))
+		}
+		return append([]byte{0x00, byte(len(program))}, program...), nil
+	case 1:
+		if len(program) != 32 {
+			return nil, xerrors.Errorf("witness v1 program must be 32 bytes, got %d", len(program))
+		}
+		return p2trScript(program), nil
+	}
+	return nil, xerrors.Errorf("witness version %d is not supported", version)
+}
+
+const (
+	bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	// bech32mConst is the BIP350 checksum constant; bech32 uses 1.
+	bech32mConst = 0x2bc830a3
+)
+
+func bech32Polymod(values []byte) uint32 {
+	gen := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= gen[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	b := make([]byte, 0, 2*len(hrp)+1)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]>>5)
+	}
+	b = append(b, 0)
+	for i := 0; i < len(hrp); i++ {
+		b = append(b, hrp[i]&31)
+	}
+	return b
+}
+
+// encodeBech32m returns the BIP350 encoding of the 5-bit values data.
+func encodeBech32m(hrp string, data []byte) string {
+	values := append(bech32HRPExpand(hrp), data...)
+	mod := bech32Polymod(append(values, 0, 0, 0, 0, 0, 0)) ^ bech32mConst
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	for i := 0; i < 6; i++ {
+		sb.WriteByte(bech32Charset[(mod>>uint(5*(5-i)))&31])
+	}
+	return sb.String()
+}
+
+// decodeBech32 decodes a bech32 or bech32m string into its lower case
+// human readable part and 5-bit values, and reports which checksum it has.
+func decodeBech32(s string) (hrp string, data []byte, isBech32m bool, err error) {
+	if len(s) > 90 {
+		return "", nil, false, xerrors.Errorf("bech32 string is %d characters", len(s))
+	}
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, false, xerrors.New("bech32 string has mixed case")
+	}
+	sep := strings.LastIndexByte(lower, '1')
+	if sep < 1 || sep+7 > len(lower) {
+		return "", nil, false, xerrors.New("bech32 separator misplaced")
+	}
+	hrp = lower[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, false, xerrors.Errorf("invalid bech32 prefix character %q", hrp[i])
+		}
+	}
+	values := make([]byte, 0, len(lower)-sep-1)
+	for i := sep + 1; i < len(lower); i++ {
+		v := strings.IndexByte(bech32Charset, lower[i])
+		if v < 0 {
+			return "", nil, false, xerrors.Errorf("invalid bech32 character %q", lower[i])
+		}
+		values = append(values, byte(v))
+	}
+	switch bech32Polymod(append(bech32HRPExpand(hrp), values...)) {
+	case 1:
+	case bech32mConst:
+		isBech32m = true
+	default:
+		return "", nil, false, xerrors.New("invalid bech32 checksum")
+	}
+	return hrp, values[:len(values)-6], isBech32m, nil
+}
@@ -0,0 +1,93 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+)
+
+func Test_s256Point_segwitAddresses(t *testing.T) {
+	for _, secret := range []int64{1, 10, 12345} {
+		p := baseMul(big.NewInt(secret))
+		for _, testnet := range []bool{false, true} {
+			params := &chaincfg.MainNetParams
+			if testnet {
+				params = &chaincfg.TestNet3Params
+			}
+			h160 := Hash160(p.Sec(true))
+			wpkh, err := btcutil.NewAddressWitnessPubKeyHash(h160, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.P2WPKHAddress(testnet); got != wpkh.EncodeAddress() {
+				t.Errorf("P2WPKHAddress(%v) = %v, want %v", testnet, got, wpkh.EncodeAddress())
+			}
+			sh, err := btcutil.NewAddressScriptHash(p2wpkhScript(h160), params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.P2SHP2WPKHAddress(testnet); got != sh.EncodeAddress() {
+				t.Errorf("P2SHP2WPKHAddress(%v) = %v, want %v", testnet, got, sh.EncodeAddress())
+			}
+		}
+	}
+}
+
+func Test_s256Point_P2TRAddress(t *testing.T) {
+	// BIP86 m/86'/0'/0'/0/0
+	internal, err := ParseXOnly(mustHex(t, "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+	if got := internal.P2TRAddress(false); got != want {
+		t.Errorf("P2TRAddress() = %v, want %v", got, want)
+	}
+	script, err := addressScript(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(script), "5120a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c"; got != want {
+		t.Errorf("addressScript() = %v, want %v", got, want)
+	}
+}
+
+func Test_addressScript_bech32m(t *testing.T) {
+	// BIP350 test vectors.
+	valid := map[string]string{
+		"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4":                     "0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0": "512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+	}
+	for address, want := range valid {
+		script, err := addressScript(address)
+		if err != nil {
+			t.Errorf("addressScript(%v) error = %v", address, err)
+			continue
+		}
+		if got := hex.EncodeToString(script); got != want {
+			t.Errorf("addressScript(%v) = %v, want %v", address, got, want)
+		}
+	}
+	for _, address := range []string{
+		// bech32 instead of bech32m
+		"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqh2y7hd",
+		// bech32m instead of bech32
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh",
+		// mixed case
+		"bc1P0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+	} {
+		if _, err := addressScript(address); err == nil {
+			t.Errorf("addressScript(%v) succeeded", address)
+		}
+	}
+	short, err := encodeSegwitAddress("bc", 1, make([]byte, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := addressScript(short); err == nil {
+		t.Errorf("addressScript(%v) accepted a 20-byte v1 program", short)
+	}
+}
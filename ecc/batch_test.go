@@ -0,0 +1,143 @@
+package ecc
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func schnorrBatch(t testing.TB, size int) []SchnorrBatchEntry {
+	t.Helper()
+	var entries []SchnorrBatchEntry
+	for _, tt := range bip340Vectors {
+		if !tt.valid {
+			continue
+		}
+		pub, err := ParseXOnly(mustHex(t, tt.publicKey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := ParseSchnorrSignature(mustHex(t, tt.signature))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, SchnorrBatchEntry{pub, mustHex(t, tt.message), sig})
+	}
+	for i := len(entries); i < size; i++ {
+		p, err := NewPrivateKey(big.NewInt(int64(1000 + 7*i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := []byte(fmt.Sprintf("message %d", i))
+		sig, err := p.SignSchnorr(msg, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// keys with an odd y are used as they are, only their x-only
+		// encoding matters.
+		entries = append(entries, SchnorrBatchEntry{p.p, msg, sig})
+	}
+	return entries
+}
+
+func TestVerifyBatch(t *testing.T) {
+	valid := schnorrBatch(t, 40)
+	bad := bip340Vectors[6]
+	badPub, _ := ParseXOnly(mustHex(t, bad.publicKey))
+	badSig, _ := ParseSchnorrSignature(mustHex(t, bad.signature))
+	badEntry := SchnorrBatchEntry{badPub, mustHex(t, bad.message), badSig}
+
+	tests := []struct {
+		name        string
+		entries     func() []SchnorrBatchEntry
+		want        bool
+		wantInvalid []int
+	}{
+		{
+			name:    "empty",
+			entries: func() []SchnorrBatchEntry { return nil },
+			want:    true,
+		},
+		{
+			name:    "valid",
+			entries: func() []SchnorrBatchEntry { return valid },
+			want:    true,
+		},
+		{
+			name: "one invalid",
+			entries: func() []SchnorrBatchEntry {
+				e := append([]SchnorrBatchEntry(nil), valid...)
+				e[3] = badEntry
+				return e
+			},
+			wantInvalid: []int{3},
+		},
+		{
+			name: "swapped messages",
+			entries: func() []SchnorrBatchEntry {
+				e := append([]SchnorrBatchEntry(nil), valid...)
+				e[10].Message, e[11].Message = e[11].Message, e[10].Message
+				return e
+			},
+			wantInvalid: []int{10, 11},
+		},
+		{
+			name: "R not on the curve",
+			entries: func() []SchnorrBatchEntry {
+				sig, _ := ParseSchnorrSignature(mustHex(t, bip340Vectors[11].signature))
+				e := append([]SchnorrBatchEntry(nil), valid...)
+				e[0].Signature = sig
+				return e
+			},
+			wantInvalid: []int{0},
+		},
+		{
+			name: "nil signature",
+			entries: func() []SchnorrBatchEntry {
+				e := append([]SchnorrBatchEntry(nil), valid[:2]...)
+				e[1].Signature = nil
+				return e
+			},
+			wantInvalid: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, invalid := VerifyBatch(tt.entries())
+			if got != tt.want {
+				t.Errorf("VerifyBatch() = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(invalid, tt.wantInvalid) {
+				t.Errorf("VerifyBatch() invalid = %v, want %v", invalid, tt.wantInvalid)
+			}
+		})
+	}
+}
+
+func TestVerifyBatch_cancellation(t *testing.T) {
+	// two invalid signatures whose errors cancel out when added with equal
+	// weights: s_1 + d and s_2 - d. Only random weights catch this.
+	entries := schnorrBatch(t, 8)
+	var d Scalar
+	d.SetBig(big.NewInt(12345))
+	s1 := *entries[6].Signature
+	s2 := *entries[7].Signature
+	s1.s.Add(&s1.s, &d)
+	s2.s.Sub(&s2.s, &d)
+	entries[6].Signature, entries[7].Signature = &s1, &s2
+	if verifyBatch(entries) {
+		t.Fatal("verifyBatch() accepted signatures that only add up")
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	for _, size := range []int{16, 128} {
+		entries := schnorrBatch(b, size)
+		b.Run(fmt.Sprint(size), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				VerifyBatch(entries)
+			}
+		})
+	}
+}
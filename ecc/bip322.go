@@ -0,0 +1,280 @@
+package ecc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"math/big"
+
+	"golang.org/x/xerrors"
+)
+
+// bip322MessageHash returns the tagged hash that BIP322 commits to.
+func bip322MessageHash(message string) [32]byte {
+	return taggedHash("BIP0322-signed-message", []byte(message))
+}
+
+// bip322ToSpend returns the virtual transaction whose only output pays to
+// script and whose input commits to message.
+func bip322ToSpend(script []byte, message string) *tx {
+	h := bip322MessageHash(message)
+	return &tx{
+		ins: []txIn{{
+			prevIndex: 0xffffffff,
+			// OP_0 PUSH32[message_hash]
+			scriptSig: append([]byte{0x00, 0x20}, h[:]...),
+		}},
+		outs: []txOut{{script: script}},
+	}
+}
+
+// bip322ToSign returns the unsigned virtual transaction that spends
+// toSpend into a single OP_RETURN output.
+func bip322ToSign(toSpend *tx) *tx {
+	return &tx{
+		ins:  []txIn{{prevHash: toSpend.txid()}},
+		outs: []txOut{{script: []byte{0x6a}}},
+	}
+}
+
+// SignBIP322Simple returns the BIP322 "simple" signature of message for
+// address: the base64 encoded witness stack of the virtual to_sign
+// transaction. It supports P2WPKH and BIP86 P2TR addresses of p; other
+// address types need SignBIP322Full.
+// https://github.com/bitcoin/bips/blob/master/bip-0322.mediawiki
+func (p *PrivateKey) SignBIP322Simple(address, message string) (string, error) {
+	toSign, err := p.bip322Sign(address, message)
+	if err != nil {
+		return "", err
+	}
+	if len(toSign.ins[0].scriptSig) != 0 {
+		return "", xerrors.Errorf("%s needs a scriptSig, use the full format", address)
+	}
+	return base64.StdEncoding.EncodeToString(appendWitness(nil, toSign.ins[0].witness)), nil
+}
+
+// SignBIP322Full returns the BIP322 "full" signature of message for
+// address: the base64 encoded to_sign transaction. It supports P2PKH,
+// P2SH-P2WPKH, P2WPKH and BIP86 P2TR addresses of p.
+func (p *PrivateKey) SignBIP322Full(address, message string) (string, error) {
+	toSign, err := p.bip322Sign(address, message)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(toSign.serialize(true)), nil
+}
+
+// bip322Sign returns the signed to_sign transaction.
+func (p *PrivateKey) bip322Sign(address, message string) (*tx, error) {
+	script, err := addressScript(address)
+	if err != nil {
+		return nil, err
+	}
+	toSign := bip322ToSign(bip322ToSpend(script, message))
+	in := &toSign.ins[0]
+	compressed := p.p.Sec(true)
+	h160 := Hash160(compressed)
+	switch {
+	case bytes.Equal(script, p2wpkhScript(h160)):
+		sig, err := p.signScript(toSign.sigHashWitnessV0(0, p2pkhScript(h160), 0))
+		if err != nil {
+			return nil, err
+		}
+		in.witness = [][]byte{sig, compressed}
+	case bytes.Equal(script, p2shScript(Hash160(p2wpkhScript(h160)))):
+		in.scriptSig = appendPushData(nil, p2wpkhScript(h160))
+		sig, err := p.signScript(toSign.sigHashWitnessV0(0, p2pkhScript(h160), 0))
+		if err != nil {
+			return nil, err
+		}
+		in.witness = [][]byte{sig, compressed}
+	case bytes.Equal(script, p2pkhScript(h160)):
+		sig, err := p.signScript(toSign.sigHashLegacy(0, script))
+		if err != nil {
+			return nil, err
+		}
+		in.scriptSig = appendPushData(appendPushData(nil, sig), compressed)
+	case bytes.Equal(script, p2pkhScript(Hash160(p.p.Sec(false)))):
+		sig, err := p.signScript(toSign.sigHashLegacy(0, script))
+		if err != nil {
+			return nil, err
+		}
+		in.scriptSig = appendPushData(appendPushData(nil, sig), p.p.Sec(false))
+	case len(script) == 34 && script[0] == 0x51:
+		tweaked, err := p.TapTweak(nil)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(script, p2trScript(tweaked.p.XOnly())) {
+			return nil, xerrors.Errorf("%s is not a supported address of this key: %w", address, ErrBIP322Unsupported)
+		}
+		prevouts := []txOut{{script: script}}
+		sig, err := tweaked.SignSchnorr(toSign.sigHashTaproot(0, prevouts, sigHashDefault), nil)
+		if err != nil {
+			return nil, err
+		}
+		in.witness = [][]byte{sig.Bytes()}
+	default:
+		return nil, xerrors.Errorf("%s is not a supported address of this key: %w", address, ErrBIP322Unsupported)
+	}
+	return toSign, nil
+}
+
+// signScript returns the low-R DER signature of digest followed by the
+// SIGHASH_ALL byte, as Bitcoin Core puts it in a script.
+func (p *PrivateKey) signScript(digest []byte) ([]byte, error) {
+	sig, err := p.SignHashLowR(digest)
+	if err != nil {
+		return nil, err
+	}
+	return append(sig.Der(), sigHashAll), nil
+}
+
+// VerifyBIP322 reports whether signature, in either the simple or the full
+// format, proves that the owner of address signed message. Signatures that
+// rely on script features this package does not implement make it return
+// an error wrapping ErrBIP322Unsupported, which BIP322 calls inconclusive.
+func VerifyBIP322(address, message, signature string) (bool, error) {
+	raw, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, xerrors.Errorf("signature is not base64: %w", err)
+	}
+	script, err := addressScript(address)
+	if err != nil {
+		return false, err
+	}
+	toSpend := bip322ToSpend(script, message)
+	toSign := bip322ToSign(toSpend)
+	if witness, err := parseWitness(raw); err == nil {
+		toSign.ins[0].witness = witness
+	} else {
+		full, err := parseTx(raw)
+		if err != nil {
+			return false, xerrors.New("signature is neither a witness stack nor a transaction")
+		}
+		if len(full.ins) != 1 {
+			return false, xerrors.Errorf("to_sign with %d inputs: %w", len(full.ins), ErrBIP322Unsupported)
+		}
+		in := full.ins[0]
+		if in.prevHash != toSign.ins[0].prevHash || in.prevIndex != 0 {
+			return false, nil
+		}
+		if len(full.outs) != 1 || full.outs[0].value != 0 || !bytes.Equal(full.outs[0].script, []byte{0x6a}) {
+			return false, nil
+		}
+		toSign = full
+	}
+	return verifyBIP322Input(toSign, script)
+}
+
+// verifyBIP322Input checks that the first input of toSign satisfies the
+// output script of to_spend.
+func verifyBIP322Input(toSign *tx, script []byte) (bool, error) {
+	in := toSign.ins[0]
+	switch {
+	case len(script) == 22 && script[0] == 0x00 && script[1] == 0x14:
+		if len(in.scriptSig) != 0 {
+			return false, nil
+		}
+		return verifyP2WPKH(toSign, script[2:], in.witness)
+	case len(script) == 23 && script[0] == 0xa9 && script[1] == 0x14 && script[22] == 0x87:
+		pushes, err := parsePushes(in.scriptSig)
+		if err != nil || len(pushes) != 1 || !bytes.Equal(Hash160(pushes[0]), script[2:22]) {
+			return false, nil
+		}
+		redeem := pushes[0]
+		if len(redeem) != 22 || redeem[0] != 0x00 || redeem[1] != 0x14 {
+			return false, xerrors.Errorf("P2SH redeem script %x: %w", redeem, ErrBIP322Unsupported)
+		}
+		return verifyP2WPKH(toSign, redeem[2:], in.witness)
+	case len(script) == 25 && bytes.Equal(script, p2pkhScript(script[3:23])):
+		if len(in.witness) != 0 {
+			return false, nil
+		}
+		pushes, err := parsePushes(in.scriptSig)
+		if err != nil || len(pushes) != 2 || !bytes.Equal(Hash160(pushes[1]), script[3:23]) {
+			return false, nil
+		}
+		return checkScriptSig(pushes[0], pushes[1], func() []byte {
+			return toSign.sigHashLegacy(0, script)
+		})
+	case len(script) == 34 && script[0] == 0x51 && script[1] == 0x20:
+		if len(in.scriptSig) != 0 {
+			return false, nil
+		}
+		return verifyP2TR(toSign, script, in.witness)
+	}
+	return false, xerrors.Errorf("output script %x: %w", script, ErrBIP322Unsupported)
+}
+
+// verifyP2WPKH checks a P2WPKH witness [signature, public key] for the
+// 20-byte program h160.
+func verifyP2WPKH(toSign *tx, h160 []byte, witness [][]byte) (bool, error) {
+	if len(witness) != 2 || !bytes.Equal(Hash160(witness[1]), h160) {
+		return false, nil
+	}
+	// segwit v0 only allows compressed keys as a standardness rule.
+	if len(witness[1]) != 33 {
+		return false, nil
+	}
+	return checkScriptSig(witness[0], witness[1], func() []byte {
+		return toSign.sigHashWitnessV0(0, p2pkhScript(h160), 0)
+	})
+}
+
+// verifyP2TR checks a BIP341 key path witness [signature] for the P2TR
+// output script. Script path spends and annexes are not implemented.
+func verifyP2TR(toSign *tx, script []byte, witness [][]byte) (bool, error) {
+	if len(witness) != 1 {
+		return false, xerrors.Errorf("taproot witness with %d items: %w", len(witness), ErrBIP322Unsupported)
+	}
+	sig := witness[0]
+	hashType := byte(sigHashDefault)
+	switch len(sig) {
+	case 64:
+	case 65:
+		// an explicit SIGHASH_DEFAULT byte is invalid.
+		if sig[64] == sigHashDefault {
+			return false, nil
+		}
+		if sig[64] != sigHashAll {
+			return false, xerrors.Errorf("sighash type %#x: %w", sig[64], ErrBIP322Unsupported)
+		}
+		hashType = sigHashAll
+	default:
+		return false, nil
+	}
+	parsed, err := ParseSchnorrSignature(sig[:64])
+	if err != nil {
+		return false, nil
+	}
+	pub, err := ParseXOnly(script[2:])
+	if err != nil {
+		return false, nil
+	}
+	prevouts := []txOut{{script: script}}
+	return pub.VerifySchnorr(toSign.sigHashTaproot(0, prevouts, hashType), parsed)
+}
+
+// checkScriptSig does what OP_CHECKSIG does for a DER signature with a
+// sighash byte and a SEC public key, under the standard policy of strict
+// DER and low-S.
+func checkScriptSig(sig, pub []byte, digest func() []byte) (bool, error) {
+	if len(sig) == 0 {
+		return false, nil
+	}
+	if sig[len(sig)-1] != sigHashAll {
+		return false, xerrors.Errorf("sighash type %#x: %w", sig[len(sig)-1], ErrBIP322Unsupported)
+	}
+	parsed, err := ParseDer(sig[:len(sig)-1])
+	if err != nil {
+		return false, nil
+	}
+	if parsed.s.Cmp(new(big.Int).Rsh(genN(), 1)) > 0 {
+		return false, nil
+	}
+	point, err := ParseSec(pub)
+	if err != nil {
+		return false, nil
+	}
+	return point.Verify(new(big.Int).SetBytes(digest()), *parsed)
+}
@@ -0,0 +1,287 @@
+package ecc
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// the addresses of the BIP322 test vectors.
+const (
+	bip322Address        = "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l"
+	bip322TaprootAddress = "bc1ppv609nr0vr25u07u95waq5lucwfm6tde4nydujnu8npg4q75mr5sxq8lt3"
+)
+
+func reversedHex(b [32]byte) string {
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func Test_bip322Transactions(t *testing.T) {
+	script, err := addressScript(bip322Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		message     string
+		messageHash string
+		toSpend     string
+		toSign      string
+	}{
+		{
+			message:     "",
+			messageHash: "c90c269c4f8fcbe6880f72a721ddfbf1914268a794cbb21cfafee13770ae19f1",
+			toSpend:     "c5680aa69bb8d860bf82d4e9cd3504b55dde018de765a91bb566283c545a99a7",
+			toSign:      "1e9654e951a5ba44c8604c4de6c67fd78a27e81dcadcfe1edf638ba3aaebaed6",
+		},
+		{
+			message:     "Hello World",
+			messageHash: "f0eb03b1a75ac6d9847f55c624a99169b5dccba2a31f5b23bea77ba270de0a7a",
+			toSpend:     "b79d196740ad5217771c1098fc4a4b51e0535c32236c71f1ea4d61a2d603352b",
+			toSign:      "88737ae86f2077145f93cc4b153ae9a1cb8d56afa511988c149c5c8c9d93bddf",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			h := bip322MessageHash(tt.message)
+			if got := hex.EncodeToString(h[:]); got != tt.messageHash {
+				t.Errorf("bip322MessageHash() = %v, want %v", got, tt.messageHash)
+			}
+			toSpend := bip322ToSpend(script, tt.message)
+			if got := reversedHex(toSpend.txid()); got != tt.toSpend {
+				t.Errorf("to_spend txid = %v, want %v", got, tt.toSpend)
+			}
+			if got := reversedHex(bip322ToSign(toSpend).txid()); got != tt.toSign {
+				t.Errorf("to_sign txid = %v, want %v", got, tt.toSign)
+			}
+		})
+	}
+}
+
+func TestPrivateKey_SignBIP322(t *testing.T) {
+	p := mustPrivateKeyFromWIF(t, "L4rK1yDtCWekvXuE6oXD9jCYfFNV2cWRpVuPLBcCU2z8TrisoyY1")
+	message := "Hello World"
+	tests := []struct {
+		name    string
+		address string
+		simple  bool
+	}{
+		{name: "P2WPKH", address: p.p.P2WPKHAddress(false), simple: true},
+		{name: "P2WPKH testnet", address: p.p.P2WPKHAddress(true), simple: true},
+		{name: "P2SH-P2WPKH", address: p.p.P2SHP2WPKHAddress(false)},
+		{name: "P2PKH", address: p.p.Addresses(true, false)},
+		{name: "P2PKH uncompressed", address: p.p.Addresses(false, true)},
+		{name: "P2TR", address: p.p.P2TRAddress(false), simple: true},
+		{name: "P2TR testnet", address: p.p.P2TRAddress(true), simple: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			full, err := p.SignBIP322Full(tt.address, message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := VerifyBIP322(tt.address, message, full)
+			if err != nil || !ok {
+				t.Errorf("VerifyBIP322() = %v, %v, want true", ok, err)
+			}
+			if ok, _ := VerifyBIP322(tt.address, message+"!", full); ok {
+				t.Error("VerifyBIP322() accepted another message")
+			}
+			simple, err := p.SignBIP322Simple(tt.address, message)
+			if (err == nil) != tt.simple {
+				t.Fatalf("PrivateKey.SignBIP322Simple() error = %v, want simple %v", err, tt.simple)
+			}
+			if tt.simple {
+				ok, err := VerifyBIP322(tt.address, message, simple)
+				if err != nil || !ok {
+					t.Errorf("VerifyBIP322() = %v, %v, want true", ok, err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyBIP322(t *testing.T) {
+	p := mustPrivateKeyFromWIF(t, "L4rK1yDtCWekvXuE6oXD9jCYfFNV2cWRpVuPLBcCU2z8TrisoyY1")
+	other := mustPrivateKeyFromWIF(t, "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn")
+	message := "Hello World"
+	address := p.p.P2WPKHAddress(false)
+	signed, err := p.bip322Sign(address, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(mutate func(*tx)) string {
+		c := *signed
+		c.ins = append([]txIn(nil), signed.ins...)
+		c.outs = append([]txOut(nil), signed.outs...)
+		mutate(&c)
+		return base64.StdEncoding.EncodeToString(c.serialize(true))
+	}
+	taprootAddress := p.p.P2TRAddress(false)
+	taprootSigned, err := p.bip322Sign(taprootAddress, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encodeTaproot := func(mutate func(*tx)) string {
+		c := *taprootSigned
+		c.ins = append([]txIn(nil), taprootSigned.ins...)
+		mutate(&c)
+		return base64.StdEncoding.EncodeToString(c.serialize(true))
+	}
+	otherSig, err := other.SignBIP322Simple(other.p.P2WPKHAddress(false), message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		address   string
+		message   string
+		signature string
+		want      bool
+		wantErr   error
+	}{
+		{
+			name:      "BIP322 empty message",
+			address:   bip322Address,
+			signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			want:      true,
+		},
+		{
+			name:      "BIP322 Hello World",
+			address:   bip322Address,
+			message:   "Hello World",
+			signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			want:      true,
+		},
+		{
+			name:      "BIP322 swapped messages",
+			address:   bip322Address,
+			message:   "Hello World",
+			signature: "AkcwRAIgM2gBAQqvZX15ZiysmKmQpDrG83avLIT492QBzLnQIxYCIBaTpOaD20qRlEylyxFSeEA2ba9YOixpX8z46TSDtS40ASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			want:      false,
+		},
+		{
+			name:      "BIP322 taproot Hello World",
+			address:   bip322TaprootAddress,
+			message:   "Hello World",
+			signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+			want:      true,
+		},
+		{
+			name:      "BIP322 taproot other message",
+			address:   bip322TaprootAddress,
+			signature: "AUHd69PrJQEv+oKTfZ8l+WROBHuy9HKrbFCJu7U1iK2iiEy1vMU5EfMtjc+VSHM7aU0SDbak5IUZRVno2P5mjSafAQ==",
+			want:      false,
+		},
+		{
+			name:    "taproot explicit SIGHASH_DEFAULT",
+			address: taprootAddress,
+			message: message,
+			signature: encodeTaproot(func(c *tx) {
+				c.ins[0].witness = [][]byte{append(append([]byte(nil), c.ins[0].witness[0]...), sigHashDefault)}
+			}),
+			want: false,
+		},
+		{
+			name:    "taproot sighash type",
+			address: taprootAddress,
+			message: message,
+			signature: encodeTaproot(func(c *tx) {
+				c.ins[0].witness = [][]byte{append(append([]byte(nil), c.ins[0].witness[0]...), 0x83)}
+			}),
+			wantErr: ErrBIP322Unsupported,
+		},
+		{
+			name:    "taproot script path",
+			address: taprootAddress,
+			message: message,
+			signature: encodeTaproot(func(c *tx) {
+				c.ins[0].witness = [][]byte{{0x51}, {0xc0}}
+			}),
+			wantErr: ErrBIP322Unsupported,
+		},
+		{
+			name:      "version 2",
+			address:   address,
+			message:   message,
+			signature: encode(func(c *tx) { c.version = 2 }),
+			// the signature commits to the version.
+			want: false,
+		},
+		{
+			name:      "other key",
+			address:   address,
+			message:   message,
+			signature: otherSig,
+			want:      false,
+		},
+		{
+			name:      "wrong prevout",
+			address:   address,
+			message:   message,
+			signature: encode(func(c *tx) { c.ins[0].prevIndex = 1 }),
+			want:      false,
+		},
+		{
+			name:    "wrong output",
+			address: address,
+			message: message,
+			signature: encode(func(c *tx) {
+				c.outs = []txOut{{value: 1, script: []byte{0x6a}}}
+			}),
+			want: false,
+		},
+		{
+			name:    "sighash type",
+			address: address,
+			message: message,
+			signature: encode(func(c *tx) {
+				sig := append([]byte(nil), c.ins[0].witness[0]...)
+				sig[len(sig)-1] = 0x81
+				c.ins[0].witness = [][]byte{sig, c.ins[0].witness[1]}
+			}),
+			wantErr: ErrBIP322Unsupported,
+		},
+		{
+			name:      "two inputs",
+			address:   address,
+			message:   message,
+			signature: encode(func(c *tx) { c.ins = append(c.ins, c.ins[0]) }),
+			wantErr:   ErrBIP322Unsupported,
+		},
+		{
+			name:      "P2WSH",
+			address:   "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3",
+			signature: otherSig,
+			wantErr:   ErrBIP322Unsupported,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyBIP322(tt.address, tt.message, tt.signature)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("VerifyBIP322() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyBIP322() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	for _, bad := range []string{"", "!!", base64.StdEncoding.EncodeToString([]byte{0x02, 0x01})} {
+		if _, err := VerifyBIP322(address, message, bad); err == nil {
+			t.Errorf("VerifyBIP322(%q) succeeded", bad)
+		}
+	}
+	if _, err := p.SignBIP322Full(other.p.P2WPKHAddress(false), message); !errors.Is(err, ErrBIP322Unsupported) {
+		t.Errorf("PrivateKey.SignBIP322Full() with a foreign address error = %v", err)
+	}
+}
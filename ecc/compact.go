@@ -0,0 +1,103 @@
+package ecc
+
+import (
+	"math/big"
+
+	"golang.org/x/xerrors"
+)
+
+const (
+	// compactSigLen is the size of a compact signature: a header byte
+	// followed by r and s as 32 bytes each.
+	compactSigLen = 65
+	// compactSigMagic is the header of a compact signature with recovery id
+	// 0 for an uncompressed public key.
+	compactSigMagic = 27
+	// compactSigCompressed is added to the header when the public key is
+	// to be serialized compressed.
+	compactSigCompressed = 4
+)
+
+// SignCompact returns a 65-byte recoverable signature of hash: a header
+// byte 27 + recovery id (+ 4 if compressed) followed by r and s. The
+// signature itself is the one SignHash produces. compressed records how the
+// public key recovered from it should be serialized.
+func (p *PrivateKey) SignCompact(hash []byte, compressed bool) ([]byte, error) {
+	sig, recoveryID, err := p.sign(hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, compactSigLen)
+	out[0] = compactSigMagic + recoveryID
+	if compressed {
+		out[0] += compactSigCompressed
+	}
+	sig.r.FillBytes(out[1:33])
+	sig.s.FillBytes(out[33:])
+	return out, nil
+}
+
+// RecoverCompact returns the public key that made the compact signature
+// sig of hash, and whether the header asks for it to be serialized
+// compressed.
+func RecoverCompact(sig, hash []byte) (*s256Point, bool, error) {
+	if len(sig) != compactSigLen {
+		return nil, false, xerrors.Errorf("compact signature must be %d bytes, got %d: %w", compactSigLen, len(sig), ErrInvalidCompactSigLength)
+	}
+	header := int(sig[0]) - compactSigMagic
+	if header < 0 || header > 7 {
+		return nil, false, xerrors.Errorf("header %d: %w", sig[0], ErrInvalidRecoveryID)
+	}
+	recoveryID := byte(header & 3)
+	compressed := header&compactSigCompressed != 0
+
+	var r, s Scalar
+	var rb, sb [32]byte
+	copy(rb[:], sig[1:33])
+	copy(sb[:], sig[33:])
+	if r.SetBytes(&rb) || r.IsZero() || s.SetBytes(&sb) || s.IsZero() {
+		return nil, false, xerrors.New("r or s is not in [1, n-1]")
+	}
+	p, err := RecoverPublicKey(NewSignature(r.Big(), s.Big()), recoveryID, hash)
+	if err != nil {
+		return nil, false, err
+	}
+	return p, compressed, nil
+}
+
+// RecoverPublicKey returns the public key Q with Verify(hash, sig) for the
+// given recovery id, computed as Q = r**-1 * (s*R - z*G), where R is the
+// point with x = r + (recoveryID >> 1) * n and the y parity of bit 0.
+// https://www.secg.org/sec1-v2.pdf section 4.1.6
+func RecoverPublicKey(sig *Signature, recoveryID byte, hash []byte) (*s256Point, error) {
+	if recoveryID > 3 {
+		return nil, xerrors.Errorf("recovery id %d: %w", recoveryID, ErrInvalidRecoveryID)
+	}
+	var r, s, z Scalar
+	if r.SetBig(sig.r) || r.IsZero() || s.SetBig(sig.s) || s.IsZero() {
+		return nil, xerrors.New("r or s is not in [1, n-1]")
+	}
+	x := new(big.Int).Set(sig.r)
+	if recoveryID&2 != 0 {
+		x.Add(x, genN())
+	}
+	fx := new(s256FieldElement)
+	if fx.SetBig(x) {
+		return nil, xerrors.Errorf("x = r + n is not below p: %w", ErrInvalidRecoveryID)
+	}
+	bigR, err := decompressS256(fx, recoveryID&1 == 1)
+	if err != nil {
+		return nil, err
+	}
+	z.SetBig(Secp256k1().hashToInt(hash))
+	// Q = (-z/r) * G + (s/r) * R
+	rInv := new(Scalar).inverseVar(&r)
+	u := new(Scalar).Mul(&z, rInv)
+	u.Negate(u)
+	v := new(Scalar).Mul(&s, rInv)
+	q := DoubleScalarMul(u.Big(), v.Big(), bigR)
+	if q.IsInfinity() {
+		return nil, xerrors.New("recovered public key is infinity")
+	}
+	return q, nil
+}
@@ -0,0 +1,151 @@
+package ecc
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+func TestPrivateKey_SignCompact(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     *big.Int
+		message    string
+		compressed bool
+	}{
+		{name: "OK if compressed", secret: big.NewInt(10), message: "test message", compressed: true},
+		{name: "OK if uncompressed", secret: big.NewInt(10), message: "test message"},
+		{name: "OK if secret is n - 1", secret: mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"), message: "recover", compressed: true},
+		{name: "OK with another key", secret: mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"), message: "recover"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPrivateKey(tt.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash := chainhash.DoubleHashB([]byte(tt.message))
+			got, err := p.SignCompact(hash, tt.compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ref, _ := btcec.PrivKeyFromBytes(btcec.S256(), tt.secret.Bytes())
+			want, err := btcec.SignCompact(btcec.S256(), ref, hash, tt.compressed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("PrivateKey.SignCompact() = %x, btcec = %x", got, want)
+			}
+			pub, compressed, err := RecoverCompact(got, hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !pub.Eq(p.p) || compressed != tt.compressed {
+				t.Errorf("RecoverCompact() = %v, %v, want %v, %v", pub, compressed, p.p, tt.compressed)
+			}
+			// a different message recovers a different key.
+			other, _, err := RecoverCompact(got, chainhash.DoubleHashB([]byte("other")))
+			if err == nil && other.Eq(p.p) {
+				t.Error("RecoverCompact() recovered the signer from another message")
+			}
+		})
+	}
+}
+
+func TestRecoverCompact_btcec(t *testing.T) {
+	ref, _ := btcec.PrivKeyFromBytes(btcec.S256(), []byte{0x01, 0x02, 0x03})
+	for i := 0; i < 8; i++ {
+		hash := chainhash.DoubleHashB([]byte{byte(i)})
+		sig, err := btcec.SignCompact(btcec.S256(), ref, hash, i%2 == 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, compressed, err := RecoverCompact(sig, hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got.Sec(true), ref.PubKey().SerializeCompressed()) {
+			t.Errorf("RecoverCompact() = %x, want %x", got.Sec(true), ref.PubKey().SerializeCompressed())
+		}
+		if compressed != (i%2 == 0) {
+			t.Errorf("RecoverCompact() compressed = %v, want %v", compressed, i%2 == 0)
+		}
+	}
+}
+
+func TestRecoverCompact_errors(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := chainhash.DoubleHashB([]byte("test message"))
+	sig, err := p.SignCompact(hash, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	with := func(f func(b []byte)) []byte {
+		b := append([]byte{}, sig...)
+		f(b)
+		return b
+	}
+	tests := []struct {
+		name    string
+		sig     []byte
+		wantErr error
+	}{
+		{name: "NG if short", sig: sig[:64], wantErr: ErrInvalidCompactSigLength},
+		{name: "NG if long", sig: append(append([]byte{}, sig...), 0), wantErr: ErrInvalidCompactSigLength},
+		{name: "NG if header is below 27", sig: with(func(b []byte) { b[0] = 26 }), wantErr: ErrInvalidRecoveryID},
+		{name: "NG if header is above 34", sig: with(func(b []byte) { b[0] = 35 }), wantErr: ErrInvalidRecoveryID},
+		{name: "NG if r is zero", sig: with(func(b []byte) { copy(b[1:33], make([]byte, 32)) })},
+		{name: "NG if s is n", sig: with(func(b []byte) { genN().FillBytes(b[33:]) })},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := RecoverCompact(tt.sig, hash)
+			if err == nil {
+				t.Fatal("RecoverCompact() should return error but nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("RecoverCompact() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := chainhash.DoubleHashB([]byte("every recovery id"))
+	sig, err := p.SignHash(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := 0
+	for id := byte(0); id < 4; id++ {
+		q, err := RecoverPublicKey(sig, id, hash)
+		if err != nil {
+			// ids 2 and 3 need r + n < p, which almost never holds.
+			continue
+		}
+		if ok, _ := q.Verify(new(big.Int).SetBytes(hash), *sig); !ok {
+			t.Errorf("RecoverPublicKey(%d) does not verify the signature", id)
+		}
+		if q.Eq(p.p) {
+			found++
+		}
+	}
+	if found != 1 {
+		t.Errorf("the signer was recovered for %d recovery ids, want 1", found)
+	}
+	if _, err := RecoverPublicKey(sig, 4, hash); !errors.Is(err, ErrInvalidRecoveryID) {
+		t.Errorf("RecoverPublicKey(4) error = %v, want %v", err, ErrInvalidRecoveryID)
+	}
+}
@@ -0,0 +1,316 @@
+package ecc
+
+import (
+	"math/big"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// Curve is a short Weierstrass curve y2 = x3 + ax + b over the integers
+// modulo the prime P, with a generator G of prime order N and cofactor H.
+// All of its methods go through the generic point arithmetic, so any curve
+// of this form can be used, from secp256k1 down to teaching curves over
+// tiny fields.
+type Curve struct {
+	Name string
+	P    *big.Int
+	A    *big.Int
+	B    *big.Int
+	G    *point
+	N    *big.Int
+	H    *big.Int
+}
+
+// NewCurve returns the curve y2 = x3 + ax + b over F_p with generator
+// (gx, gy) of order n and cofactor h. It checks that the generator lies on
+// the curve and that n * G is the point at infinity.
+func NewCurve(name string, p, a, b, gx, gy, n, h *big.Int) (*Curve, error) {
+	if p.Cmp(big.NewInt(3)) < 0 || !p.ProbablyPrime(20) {
+		return nil, xerrors.Errorf("%v is not an odd prime", p)
+	}
+	if n.Sign() <= 0 || !n.ProbablyPrime(20) {
+		return nil, xerrors.Errorf("order %v is not prime", n)
+	}
+	c := &Curve{
+		Name: name,
+		P:    new(big.Int).Set(p),
+		A:    new(big.Int).Mod(a, p),
+		B:    new(big.Int).Mod(b, p),
+		N:    new(big.Int).Set(n),
+		H:    new(big.Int).Set(h),
+	}
+	g, err := c.NewPoint(gx, gy)
+	if err != nil {
+		return nil, xerrors.Errorf("invalid generator: %w", err)
+	}
+	inf, err := g.FastRMul(c.N)
+	if err != nil {
+		return nil, err
+	}
+	if !inf.IsInfinity() {
+		return nil, xerrors.Errorf("generator does not have order %v", n)
+	}
+	c.G = g
+	return c, nil
+}
+
+// mustNewCurve builds one of the well-known curves. Their parameters are
+// trusted, so only the cheap on-curve check of G is done here; the tests run
+// them through NewCurve.
+func mustNewCurve(name, p, a, b, gx, gy, n string, h int64) *Curve {
+	c := &Curve{
+		Name: name,
+		P:    mustBigFromHex(p),
+		A:    mustBigFromHex(a),
+		B:    mustBigFromHex(b),
+		N:    mustBigFromHex(n),
+		H:    big.NewInt(h),
+	}
+	g, err := c.NewPoint(mustBigFromHex(gx), mustBigFromHex(gy))
+	if err != nil {
+		panic(err)
+	}
+	c.G = g
+	return c
+}
+
+var (
+	secp256k1Once  sync.Once
+	secp256k1Curve *Curve
+	secp256r1Once  sync.Once
+	secp256r1Curve *Curve
+)
+
+// Secp256k1 returns the curve used by Bitcoin. The parameters are built once
+// and shared, so they must not be modified.
+func Secp256k1() *Curve {
+	secp256k1Once.Do(func() {
+		secp256k1Curve = mustNewCurve("secp256k1",
+			"fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f",
+			"0",
+			"7",
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8",
+			"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141",
+			1,
+		)
+	})
+	return secp256k1Curve
+}
+
+// Secp256r1 returns the NIST P-256 curve. The parameters are built once and
+// shared, so they must not be modified.
+func Secp256r1() *Curve {
+	secp256r1Once.Do(func() {
+		secp256r1Curve = mustNewCurve("secp256r1",
+			"ffffffff00000001000000000000000000000000ffffffffffffffffffffffff",
+			"ffffffff00000001000000000000000000000000fffffffffffffffffffffffc",
+			"5ac635d8aa3a93e7b3ebbd55769886bc651d06b0cc53b0f63bce3c3e27d2604b",
+			"6b17d1f2e12c4247f8bce6e563a440f277037d812deb33a0f4a13945d898c296",
+			"4fe342e2fe1a7f9b8ee7eb4a7c0f9e162bce33576b315ececbb6406837bf51f5",
+			"ffffffff00000000ffffffffffffffffbce6faada7179e84f3b9cac2fc632551",
+			1,
+		)
+	})
+	return secp256r1Curve
+}
+
+func (c *Curve) fieldElement(number *big.Int) (*fieldElement, error) {
+	if number.Sign() < 0 {
+		return nil, xerrors.New("number is negative")
+	}
+	return NewFieldElement(new(big.Int).Set(number), c.P)
+}
+
+// coefficients returns a and b as field elements that the caller may keep.
+func (c *Curve) coefficients() (a, b *fieldElement) {
+	return &fieldElement{new(big.Int).Set(c.A), c.P}, &fieldElement{new(big.Int).Set(c.B), c.P}
+}
+
+// Infinity returns the point at infinity on c.
+func (c *Curve) Infinity() *point {
+	a, b := c.coefficients()
+	return &point{nil, nil, a, b}
+}
+
+// NewPoint returns the point (x, y) on c, or an error if it is not on it.
+func (c *Curve) NewPoint(x, y *big.Int) (*point, error) {
+	fx, err := c.fieldElement(x)
+	if err != nil {
+		return nil, err
+	}
+	fy, err := c.fieldElement(y)
+	if err != nil {
+		return nil, err
+	}
+	a, b := c.coefficients()
+	return NewPoint(fx, fy, a, b)
+}
+
+// IsOnCurve reports whether (x, y) is a point on c.
+func (c *Curve) IsOnCurve(x, y *big.Int) bool {
+	_, err := c.NewPoint(x, y)
+	return err == nil
+}
+
+// Validate checks that p can be used as a public key on c: it must be a
+// finite point of c that lies in the subgroup generated by G.
+func (c *Curve) Validate(p *point) error {
+	if p == nil || p.IsInfinity() {
+		return xerrors.New("point is infinity")
+	}
+	if a, b := c.coefficients(); !p.a.Equal(a) || !p.b.Equal(b) {
+		return xerrors.Errorf("point is not on %s", c.Name)
+	}
+	if !c.IsOnCurve(p.x.number, p.y.number) {
+		return xerrors.Errorf("point is not on %s", c.Name)
+	}
+	if c.H.Cmp(big.NewInt(1)) != 0 {
+		inf, err := p.FastRMul(c.N)
+		if err != nil {
+			return err
+		}
+		if !inf.IsInfinity() {
+			return xerrors.New("point is not in the subgroup generated by G")
+		}
+	}
+	return nil
+}
+
+// ScalarBaseMul returns k * G.
+func (c *Curve) ScalarBaseMul(k *big.Int) (*point, error) {
+	return c.G.FastRMul(new(big.Int).Mod(k, c.N))
+}
+
+// byteLen is the length of one encoded coordinate.
+func (c *Curve) byteLen() int {
+	return (c.P.BitLen() + 7) / 8
+}
+
+// Sec returns the SEC1 encoding of p.
+func (c *Curve) Sec(p *point, compressed bool) []byte {
+	size := c.byteLen()
+	x := p.x.number.FillBytes(make([]byte, size))
+	if !compressed {
+		y := p.y.number.FillBytes(make([]byte, size))
+		return append(append([]byte{0x04}, x...), y...)
+	}
+	if p.y.number.Bit(0) == 0 {
+		return append([]byte{0x02}, x...)
+	}
+	return append([]byte{0x03}, x...)
+}
+
+// ParseSec decodes a SEC1 encoded point on c.
+func (c *Curve) ParseSec(bin []byte) (*point, error) {
+	size := c.byteLen()
+	if len(bin) == 0 {
+		return nil, xerrors.Errorf("empty SEC encoding: %w", ErrInvalidPubKeyLength)
+	}
+	switch bin[0] {
+	case 0x04:
+		if len(bin) != 1+2*size {
+			return nil, xerrors.Errorf("uncompressed SEC encoding must be %d bytes, got %d: %w", 1+2*size, len(bin), ErrInvalidPubKeyLength)
+		}
+		p, err := c.NewPoint(new(big.Int).SetBytes(bin[1:1+size]), new(big.Int).SetBytes(bin[1+size:]))
+		if err != nil {
+			return nil, xerrors.Errorf("%v: %w", err, ErrPubKeyNotOnCurve)
+		}
+		return p, nil
+	case 0x02, 0x03:
+		if len(bin) != 1+size {
+			return nil, xerrors.Errorf("compressed SEC encoding must be %d bytes, got %d: %w", 1+size, len(bin), ErrInvalidPubKeyLength)
+		}
+	default:
+		return nil, xerrors.Errorf("prefix %#x: %w", bin[0], ErrInvalidPubKeyPrefix)
+	}
+	x, err := c.fieldElement(new(big.Int).SetBytes(bin[1:]))
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, ErrPubKeyNotOnCurve)
+	}
+	// right = x**3 + ax + b
+	a, b := c.coefficients()
+	right := x.clone()
+	right.Mul(right, x)
+	right.Add(right, a)
+	right.Mul(right, x)
+	right.Add(right, b)
+	y, err := right.clone().Sqrt(right)
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, ErrPubKeyNotOnCurve)
+	}
+	if y.number.Bit(0) != uint(bin[0]&1) {
+		y.Neg(y)
+	}
+	return c.NewPoint(x.number, y.number)
+}
+
+// hashToInt converts a message hash to an integer modulo N by keeping its
+// leftmost N.BitLen() bits, as ECDSA specifies.
+func (c *Curve) hashToInt(hash []byte) *big.Int {
+	z := new(big.Int).SetBytes(hash)
+	if excess := len(hash)*8 - c.N.BitLen(); excess > 0 {
+		z.Rsh(z, uint(excess))
+	}
+	return z
+}
+
+// Sign returns an ECDSA signature of hash by the private key secret, using
+// RFC 6979 nonces.
+func (c *Curve) Sign(secret *big.Int, hash []byte) (*Signature, error) {
+	if secret.Sign() <= 0 || secret.Cmp(c.N) >= 0 {
+		return nil, xerrors.New("private key is out of range")
+	}
+	z := c.hashToInt(hash)
+	nonces := newRFC6979(c.N, secret, hash, nil)
+	for {
+		k := nonces.next()
+		kG, err := c.ScalarBaseMul(k)
+		if err != nil {
+			return nil, err
+		}
+		// r = (k*G).x mod n
+		r := new(big.Int).Mod(kG.x.number, c.N)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = (z + r*secret) / k mod n
+		s := new(big.Int).Mul(r, secret)
+		s.Add(s, z).Mul(s, new(big.Int).ModInverse(k, c.N)).Mod(s, c.N)
+		if s.Sign() == 0 {
+			continue
+		}
+		return NewSignature(r, s), nil
+	}
+}
+
+// Verify reports whether sig is a valid ECDSA signature of hash by pub.
+func (c *Curve) Verify(pub *point, hash []byte, sig *Signature) bool {
+	if c.Validate(pub) != nil {
+		return false
+	}
+	if sig.r.Sign() <= 0 || sig.r.Cmp(c.N) >= 0 || sig.s.Sign() <= 0 || sig.s.Cmp(c.N) >= 0 {
+		return false
+	}
+	z := c.hashToInt(hash)
+	sInv := new(big.Int).ModInverse(sig.s, c.N)
+	// u = z / s, v = r / s
+	u := new(big.Int).Mul(z, sInv)
+	u.Mod(u, c.N)
+	v := new(big.Int).Mul(sig.r, sInv)
+	v.Mod(v, c.N)
+	uG, err := c.ScalarBaseMul(u)
+	if err != nil {
+		return false
+	}
+	vP, err := pub.FastRMul(v)
+	if err != nil {
+		return false
+	}
+	total, err := uG.Add(vP)
+	if err != nil || total.IsInfinity() {
+		return false
+	}
+	return new(big.Int).Mod(total.x.number, c.N).Cmp(sig.r) == 0
+}
@@ -0,0 +1,180 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func newF223Curve(t *testing.T) *Curve {
+	// y2 = x3 + 7 over F223 has 252 points; (15, 86) generates its subgroup
+	// of order 7.
+	c, err := NewCurve("F223", big.NewInt(223), big.NewInt(0), big.NewInt(7), big.NewInt(15), big.NewInt(86), big.NewInt(7), big.NewInt(36))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestNewCurve(t *testing.T) {
+	for _, c := range []*Curve{Secp256k1(), Secp256r1()} {
+		t.Run(c.Name, func(t *testing.T) {
+			if _, err := NewCurve(c.Name, c.P, c.A, c.B, c.G.x.number, c.G.y.number, c.N, c.H); err != nil {
+				t.Errorf("NewCurve() error = %v", err)
+			}
+		})
+	}
+	tests := []struct {
+		name   string
+		p      int64
+		gx, gy int64
+		n      int64
+	}{
+		{name: "Error if p is not prime", p: 221, gx: 15, gy: 86, n: 7},
+		{name: "Error if G is not on the curve", p: 223, gx: 15, gy: 87, n: 7},
+		{name: "Error if n is not the order of G", p: 223, gx: 15, gy: 86, n: 5},
+		{name: "Error if n is not prime", p: 223, gx: 47, gy: 71, n: 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCurve("bad", big.NewInt(tt.p), big.NewInt(0), big.NewInt(7), big.NewInt(tt.gx), big.NewInt(tt.gy), big.NewInt(tt.n), big.NewInt(1))
+			if err == nil {
+				t.Error("NewCurve() should return error but nil")
+			}
+		})
+	}
+}
+
+func TestCurve_matchesS256Point(t *testing.T) {
+	c := Secp256k1()
+	for _, k := range []*big.Int{big.NewInt(7), big.NewInt(1485), mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5")} {
+		got, err := c.ScalarBaseMul(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := baseMul(k)
+		for _, compressed := range []bool{true, false} {
+			sec := c.Sec(got, compressed)
+			if string(sec) != string(want.Sec(compressed)) {
+				t.Errorf("Curve.Sec(%v*G) = %x, want %x", k, sec, want.Sec(compressed))
+			}
+			parsed, err := c.ParseSec(sec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !parsed.Eq(got) {
+				t.Errorf("Curve.ParseSec(%x) = %v, want %v", sec, parsed, got)
+			}
+		}
+	}
+}
+
+func TestCurve_Secp256r1_matchesStdlib(t *testing.T) {
+	c := Secp256r1()
+	std := elliptic.P256()
+	secret := mustGetFromHex("0xc9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	pub, err := c.ScalarBaseMul(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := std.ScalarBaseMult(secret.Bytes())
+	if pub.x.number.Cmp(x) != 0 || pub.y.number.Cmp(y) != 0 {
+		t.Fatalf("Curve.ScalarBaseMul() = %v, want (%x, %x)", pub, x, y)
+	}
+	parsed, err := c.ParseSec(c.Sec(pub, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Eq(pub) {
+		t.Errorf("Curve.ParseSec() = %v, want %v", parsed, pub)
+	}
+
+	hash := sha256.Sum256([]byte("sample"))
+	sig, err := c.Sign(secret, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// RFC 6979 A.2.5, P-256 with SHA-256 and the message "sample".
+	wantR := mustGetFromHex("0xefd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716")
+	wantS := mustGetFromHex("0xf7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8")
+	if sig.r.Cmp(wantR) != 0 || sig.s.Cmp(wantS) != 0 {
+		t.Errorf("Curve.Sign() = r:%x s:%x, want r:%x s:%x", sig.r, sig.s, wantR, wantS)
+	}
+	stdPub := &ecdsa.PublicKey{Curve: std, X: x, Y: y}
+	if !ecdsa.Verify(stdPub, hash[:], sig.r, sig.s) {
+		t.Error("crypto/ecdsa rejected the signature")
+	}
+	if !c.Verify(pub, hash[:], sig) {
+		t.Error("Curve.Verify() rejected the signature")
+	}
+	hash[0] ^= 1
+	if c.Verify(pub, hash[:], sig) {
+		t.Error("Curve.Verify() accepted a signature of another message")
+	}
+}
+
+func TestCurve_ParseSec_p1mod4(t *testing.T) {
+	// y2 = x3 + 7 over F97 has 79 points. 97 = 1 mod 4, so decompression
+	// needs Tonelli-Shanks.
+	c, err := NewCurve("F97", big.NewInt(97), big.NewInt(0), big.NewInt(7), big.NewInt(1), big.NewInt(28), big.NewInt(79), big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k := int64(1); k < 79; k++ {
+		pub, err := c.ScalarBaseMul(big.NewInt(k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := c.ParseSec(c.Sec(pub, true))
+		if err != nil {
+			t.Fatalf("Curve.ParseSec(%d*G) error = %v", k, err)
+		}
+		if !parsed.Eq(pub) {
+			t.Errorf("Curve.ParseSec(%d*G) = %v, want %v", k, parsed, pub)
+		}
+	}
+}
+
+func TestCurve_F223(t *testing.T) {
+	c := newF223Curve(t)
+	hash := Hash256([]byte("teaching curve"))
+	for secret := int64(1); secret < 7; secret++ {
+		pub, err := c.ScalarBaseMul(big.NewInt(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Validate(pub); err != nil {
+			t.Fatalf("Curve.Validate(%v) error = %v", pub, err)
+		}
+		parsed, err := c.ParseSec(c.Sec(pub, true))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !parsed.Eq(pub) {
+			t.Errorf("Curve.ParseSec() = %v, want %v", parsed, pub)
+		}
+		sig, err := c.Sign(big.NewInt(secret), hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !c.Verify(pub, hash, sig) {
+			t.Errorf("Curve.Verify() rejected the signature by %d", secret)
+		}
+	}
+	// (47, 71) is on the curve but outside the subgroup of order 7.
+	outside, err := c.NewPoint(big.NewInt(47), big.NewInt(71))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Validate(outside); err == nil {
+		t.Error("Curve.Validate() should reject a point outside the subgroup")
+	}
+	if err := c.Validate(c.Infinity()); err == nil {
+		t.Error("Curve.Validate() should reject infinity")
+	}
+	if _, err := c.Sign(big.NewInt(7), hash); err == nil {
+		t.Error("Curve.Sign() should reject a private key equal to n")
+	}
+}
@@ -57,231 +57,79 @@ func NewPoint(x, y, a, b *fieldElement) (*point, error) {
 	return &point{x, y, a, b}, nil
 }
 
-func (p *point) Eq(other *point) bool {
-	// TODO: nil(infinity)の場合でも正常に比較できるようにする
-	return p.x.Equal(other.x) &&
-		p.y.Equal(other.y) &&
-		p.a.Equal(other.a) &&
-		p.b.Equal(other.b)
+// Infinity returns the point at infinity, the identity of the group, on the
+// curve y2 = x3 + ax + b.
+func Infinity(a, b *fieldElement) *point {
+	return &point{nil, nil, a.clone(), b.clone()}
 }
 
-func (p *point) Add(other *point) error {
-	if !p.a.Equal(other.a) || !p.b.Equal(other.b) {
-		return xerrors.Errorf("(%v, %v) is not on the same curve", p, other)
+// IsInfinity reports whether p is the point at infinity.
+func (p *point) IsInfinity() bool {
+	return p.x == nil
+}
+
+// clone returns a copy of p that shares no field elements with it.
+func (p *point) clone() *point {
+	return &point{p.x.clone(), p.y.clone(), p.a.clone(), p.b.clone()}
+}
+
+func (p *point) Eq(other *point) bool {
+	if other == nil {
+		return false
 	}
-	if p.x == nil {
-		p.x = other.x
-		p.y = other.y
-		return nil
+	if !p.a.Equal(other.a) || !p.b.Equal(other.b) {
+		return false
 	}
-	if other.x == nil {
-		return nil
+	if p.IsInfinity() || other.IsInfinity() {
+		return p.IsInfinity() && other.IsInfinity()
 	}
-	if !p.x.Equal(other.x) {
-		// s = (other.y - self.y) / (other.x - self.x)
-		subOtherY, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		subOtherY, err = subOtherY.Sub(other.y, p.y)
-		if err != nil {
-			return err
-		}
-		subOtherX, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		subOtherX, err = subOtherX.Sub(other.x, p.x)
-		if err != nil {
-			return err
-		}
-		s, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		s, err = s.Div(subOtherY, subOtherX)
-		if err != nil {
-			return err
-		}
-		x, err := NewFieldElement(big.NewInt(0).Set(p.x.number), p.x.prime)
-		if err != nil {
-			return err
-		}
-		// x = s**2 - self.x - other.x
-		x, err = x.Pow(s, big.NewInt(2))
-		if err != nil {
-			return err
-		}
-		x, err = x.Sub(x, p.x)
-		if err != nil {
-			return err
-		}
-		x, err = x.Sub(x, other.x)
-		if err != nil {
-			return err
-		}
-		// y = s * (self.x - x) - self.y
-		mulS, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		mulS, err = mulS.Sub(p.x, x)
-		if err != nil {
-			return err
-		}
-		mulS, err = mulS.Mul(s, mulS)
-		if err != nil {
-			return err
-		}
-		y, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		y, err = y.Sub(mulS, p.y)
-		if err != nil {
-			return err
-		}
-		p.x = x
-		p.y = y
-		return nil
+	return p.x.Equal(other.x) && p.y.Equal(other.y)
+}
+
+// Add returns p + other as a new point. Neither p nor other is modified.
+func (p *point) Add(other *point) (*point, error) {
+	if !p.a.Equal(other.a) || !p.b.Equal(other.b) {
+		return nil, xerrors.Errorf("(%v, %v) is not on the same curve", p, other)
 	}
-	if p.Eq(other) {
-		// s=(3*x1**2+a)/(2*y1)
-		powX, err := NewFieldElement(big.NewInt(0).Set(p.x.number), p.x.prime)
-		if err != nil {
-			return err
-		}
-		powX, err = powX.Pow(powX, big.NewInt(2))
-		if err != nil {
-			return err
-		}
-		tripleX, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		triple, err := NewFieldElement(big.NewInt(3), p.x.prime)
-		if err != nil {
-			return err
-		}
-		tripleX, err = tripleX.Mul(powX, triple)
-		if err != nil {
-			return err
-		}
-		tripleX, err = tripleX.Add(tripleX, p.a)
-		if err != nil {
-			return err
-		}
-		doubleY, err := NewFieldElement(big.NewInt(0).Set(p.y.number), p.x.prime)
-		if err != nil {
-			return err
-		}
-		double, err := NewFieldElement(big.NewInt(2), p.x.prime)
-		if err != nil {
-			return err
-		}
-		doubleY, err = doubleY.Mul(doubleY, double)
-		if err != nil {
-			return err
-		}
-		s, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		s, err = s.Div(tripleX, doubleY)
-		if err != nil {
-			return err
-		}
-		// x = s**2 - 2 * self.x
-		x, err := NewFieldElement(big.NewInt(0).Set(p.x.number), p.x.prime)
-		if err != nil {
-			return err
-		}
-		x, err = x.Pow(s, big.NewInt(2))
-		if err != nil {
-			return err
-		}
-		x, err = x.Sub(x, p.x)
-		if err != nil {
-			return err
-		}
-		x, err = x.Sub(x, other.x)
-		if err != nil {
-			return err
-		}
-		// y = s * (self.x - x) - self.y
-		mulS, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		mulS, err = mulS.Sub(p.x, x)
-		if err != nil {
-			return err
-		}
-		mulS, err = mulS.Mul(s, mulS)
-		if err != nil {
-			return err
-		}
-		y, err := NewFieldElement(big.NewInt(0), p.x.prime)
-		if err != nil {
-			return err
-		}
-		y, err = y.Sub(mulS, p.y)
-		if err != nil {
-			return err
-		}
-		p.x = x
-		p.y = y
-		return nil
+	if p.IsInfinity() {
+		return other.clone(), nil
 	}
-	if p.x.Equal(other.x) && !p.y.Equal(other.y) {
-		p.x = nil
-		p.y = nil
-		return nil
+	if other.IsInfinity() {
+		return p.clone(), nil
 	}
-	return nil
+	c := newJacobianCurve(p)
+	x, y := c.toAffine(c.addMixed(c.fromAffine(p), other))
+	return &point{x, y, p.a.clone(), p.b.clone()}, nil
 }
 
-func (p *point) RMul(coefficient *big.Int) error {
-	product, err := NewPoint(nil, nil, p.a, p.b)
-	if err != nil {
-		return xerrors.New("failed to new point")
-	}
-	for i := big.NewInt(0); i.Cmp(coefficient) != 0; i.Add(i, big.NewInt(1)) {
-		err = product.Add(p)
+// RMul returns coefficient * p by repeated addition. It is only meant for
+// small coefficients; use FastRMul otherwise.
+func (p *point) RMul(coefficient *big.Int) (*point, error) {
+	product := Infinity(p.a, p.b)
+	for i := big.NewInt(0); i.Cmp(coefficient) < 0; i.Add(i, big.NewInt(1)) {
+		var err error
+		product, err = product.Add(p)
 		if err != nil {
-			return xerrors.Errorf("failed to add point, %v", err)
+			return nil, xerrors.Errorf("failed to add point, %v", err)
 		}
 	}
-	p.x = product.x
-	p.y = product.y
-	return nil
+	return product, nil
 }
 
-func (p *point) FastRMul(coefficient *big.Int) error {
-	coef := coefficient
-	current := p
-	result, err := NewPoint(nil, nil, p.a, p.b)
-	if err != nil {
-		return xerrors.New("failed to new point")
-	}
-	for {
-		// coef & 1 != 0
-		if big.NewInt(0).And(coef, big.NewInt(1)).Cmp(big.NewInt(0)) != 0 {
-			err := result.Add(current)
-			if err != nil {
-				return xerrors.Errorf("failed to add point, %v", err)
-			}
-		}
-		err := current.Add(current)
-		if err != nil {
-			return xerrors.Errorf("failed to add point, %v", err)
-		}
-		coef.Rsh(coef, 1)
-		if coef.Cmp(big.NewInt(0)) == 0 {
-			break
-		}
-	}
-	p.x = result.x
-	p.y = result.y
-	return nil
+// FastRMul returns coefficient * p using double-and-add. Neither p nor
+// coefficient is modified.
+func (p *point) FastRMul(coefficient *big.Int) (*point, error) {
+	if coefficient.Sign() < 0 {
+		return nil, xerrors.New("coefficient must not be negative")
+	}
+	c := newJacobianCurve(p)
+	result := c.infinity()
+	for i := coefficient.BitLen() - 1; i >= 0; i-- {
+		result = c.double(result)
+		if coefficient.Bit(i) == 1 {
+			result = c.addMixed(result, p)
+		}
+	}
+	x, y := c.toAffine(result)
+	return &point{x, y, p.a.clone(), p.b.clone()}, nil
 }
@@ -151,13 +151,13 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
 		}
-		if !po.Eq(other) {
-			t.Errorf("point.Add() = %v want %v", po, other)
+		if !got.Eq(other) {
+			t.Errorf("point.Add() = %v want %v", got, other)
 		}
 	})
 	t.Run("Ok if it called with other.x as infinity", func(t *testing.T) {
@@ -179,7 +179,7 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
@@ -193,8 +193,8 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !po.Eq(want) {
-			t.Errorf("point.Add() = %v want %v", po, other)
+		if !got.Eq(want) {
+			t.Errorf("point.Add() = %v want %v", got, want)
 		}
 	})
 	t.Run("Ok if it called with different x case1", func(t *testing.T) {
@@ -216,7 +216,7 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
@@ -230,8 +230,8 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !po.Eq(want) {
-			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", po.x.number, po.y.number, want.x.number, want.y.number)
+		if !got.Eq(want) {
+			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, want.x.number, want.y.number)
 		}
 	})
 	t.Run("Ok if it called with different x case2", func(t *testing.T) {
@@ -253,7 +253,7 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
@@ -267,8 +267,8 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !po.Eq(want) {
-			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", po.x.number, po.y.number, want.x.number, want.y.number)
+		if !got.Eq(want) {
+			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, want.x.number, want.y.number)
 		}
 	})
 
@@ -291,13 +291,13 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
 		}
-		if po.x != nil || po.y != nil {
-			t.Errorf("point.Add() = %v,%v want %v,%v", po.x.number, po.y.number, nil, nil)
+		if !got.IsInfinity() {
+			t.Errorf("point.Add() = %v,%v want %v,%v", got.x.number, got.y.number, nil, nil)
 		}
 	})
 	t.Run("Ok if it called with same point", func(t *testing.T) {
@@ -319,7 +319,7 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		got, err := po.Add(other)
 		if err != nil {
 			t.Errorf("point.Add() error = %v", err)
 			return
@@ -333,8 +333,8 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		if !po.Eq(want) {
-			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", po.x.number, po.y.number, want.x.number, want.y.number)
+		if !got.Eq(want) {
+			t.Errorf("point.Add() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, want.x.number, want.y.number)
 		}
 	})
 	t.Run("Error if it called with different curve", func(t *testing.T) {
@@ -356,7 +356,7 @@ func Test_point_Add(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
-		err = po.Add(other)
+		_, err = po.Add(other)
 		if err == nil {
 			t.Errorf("point.Add() should return error but nil")
 			return
@@ -404,11 +404,13 @@ func Test_point_RMul(t *testing.T) {
 				a: tt.fields.a,
 				b: tt.fields.b,
 			}
-			if err := p.RMul(tt.fields.coefficient); (err != nil) != tt.wantErr {
+			got, err := p.RMul(tt.fields.coefficient)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("point.RMul() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if !p.Eq(tt.want) {
-				t.Errorf("point.RMul() = x:%v y:%v want x:%v y:%v", p.x.number, p.y.number, tt.want.x.number, tt.want.y.number)
+			if !got.Eq(tt.want) {
+				t.Errorf("point.RMul() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, tt.want.x.number, tt.want.y.number)
 			}
 		})
 	}
@@ -470,12 +472,79 @@ func Test_point_FastRMul(t *testing.T) {
 				a: tt.fields.a,
 				b: tt.fields.b,
 			}
-			if err := p.FastRMul(tt.fields.coefficient); (err != nil) != tt.wantErr {
+			got, err := p.FastRMul(tt.fields.coefficient)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("point.FastRMul() error = %v, wantErr %v", err, tt.wantErr)
+				return
 			}
-			if !p.Eq(tt.want) {
-				t.Errorf("point.FastRMul() = x:%v y:%v want x:%v y:%v", p.x.number, p.y.number, tt.want.x.number, tt.want.y.number)
+			if !got.Eq(tt.want) {
+				t.Errorf("point.FastRMul() = x:%v y:%v want x:%v y:%v", got.x.number, got.y.number, tt.want.x.number, tt.want.y.number)
 			}
 		})
 	}
 }
+
+func Test_point_Infinity(t *testing.T) {
+	p := big.NewInt(223)
+	a := &fieldElement{big.NewInt(0), p}
+	b := &fieldElement{big.NewInt(7), p}
+	inf := Infinity(a, b)
+	if !inf.IsInfinity() {
+		t.Fatal("Infinity() should be infinity")
+	}
+	po, err := NewPoint(&fieldElement{big.NewInt(47), p}, &fieldElement{big.NewInt(71), p}, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if po.IsInfinity() {
+		t.Error("(47, 71) should not be infinity")
+	}
+	if !inf.Eq(Infinity(a, b)) {
+		t.Error("infinity should equal infinity on the same curve")
+	}
+	if inf.Eq(Infinity(&fieldElement{big.NewInt(5), p}, b)) {
+		t.Error("infinity should not equal infinity on another curve")
+	}
+	if inf.Eq(po) || po.Eq(inf) {
+		t.Error("infinity should not equal a finite point")
+	}
+	// 21 is the order of (47, 71).
+	got, err := po.FastRMul(big.NewInt(21))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Eq(inf) {
+		t.Errorf("21*(47, 71) = %v want infinity", got)
+	}
+}
+
+func Test_point_doesNotAliasInputs(t *testing.T) {
+	p := big.NewInt(223)
+	a := &fieldElement{big.NewInt(0), p}
+	b := &fieldElement{big.NewInt(7), p}
+	po, err := NewPoint(&fieldElement{big.NewInt(47), p}, &fieldElement{big.NewInt(71), p}, a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	coefficient := big.NewInt(8)
+	got, err := po.FastRMul(coefficient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if coefficient.Cmp(big.NewInt(8)) != 0 {
+		t.Errorf("coefficient was modified to %v", coefficient)
+	}
+	if po.x.number.Cmp(big.NewInt(47)) != 0 || po.y.number.Cmp(big.NewInt(71)) != 0 {
+		t.Errorf("receiver was modified to x:%v y:%v", po.x.number, po.y.number)
+	}
+	sum, err := got.Add(Infinity(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sum.x.Add(sum.x, sum.x); err != nil {
+		t.Fatal(err)
+	}
+	if got.x.number.Cmp(big.NewInt(116)) != 0 {
+		t.Errorf("result of Add shares its x with the input, got x:%v", got.x.number)
+	}
+}
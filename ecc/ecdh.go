@@ -0,0 +1,95 @@
+package ecc
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/xerrors"
+)
+
+// ECDHHashFunc derives the shared secret from the 32-byte big-endian
+// coordinates of the shared point, like the hashfp argument of
+// secp256k1_ecdh in libsecp256k1.
+type ECDHHashFunc func(x, y []byte) []byte
+
+// ECDHHashSHA256 is the default hash of libsecp256k1 and BOLT8: SHA256 of
+// the compressed encoding of the shared point.
+func ECDHHashSHA256(x, y []byte) []byte {
+	prefix := byte(0x02) | y[31]&1
+	h := sha256.New()
+	h.Write([]byte{prefix})
+	h.Write(x)
+	return h.Sum(nil)
+}
+
+// ECDHHashRawX returns the x coordinate of the shared point unhashed, as
+// btcec.GenerateSharedSecret and the ECIES of many libraries do.
+func ECDHHashRawX(x, _ []byte) []byte {
+	out := make([]byte, 32)
+	copy(out, x)
+	return out
+}
+
+// ECDH returns the shared secret of p and pub with ECDHHashSHA256.
+func (p *PrivateKey) ECDH(pub *s256Point) ([]byte, error) {
+	return p.ECDHWithHash(pub, ECDHHashSHA256)
+}
+
+// ECDHRaw returns the 32-byte x coordinate of secret*pub.
+func (p *PrivateKey) ECDHRaw(pub *s256Point) ([]byte, error) {
+	return p.ECDHWithHash(pub, ECDHHashRawX)
+}
+
+// ECDHWithHash returns hash applied to the coordinates of secret*pub. pub
+// must be a finite point on the curve: multiplying a point off the curve
+// would leak bits of the secret through an invalid curve attack.
+func (p *PrivateKey) ECDHWithHash(pub *s256Point, hash ECDHHashFunc) ([]byte, error) {
+	if pub == nil || pub.IsInfinity() {
+		return nil, xerrors.Errorf("ECDH: %w", ErrPubKeyInfinity)
+	}
+	if !pub.onCurve() {
+		return nil, xerrors.Errorf("ECDH: %w", ErrPubKeyNotOnCurve)
+	}
+	shared := pub.constTimeMul(&p.secret)
+	x, y := shared.x.Bytes(), shared.y.Bytes()
+	return hash(x[:], y[:]), nil
+}
+
+// constTimeMul returns k * s with 4-bit fixed windows for a secret k. Like
+// ScalarBaseMul every window reads the whole table and adds with
+// addMixedConst, so unlike the wNAF of ScalarMul neither the memory access
+// pattern nor the branches depend on k. The table only depends on s, which
+// must not be infinity.
+func (s *s256Point) constTimeMul(k *Scalar) *s256Point {
+	// table[i] = (i+1) * s
+	var table [15]s256Point
+	table[0] = *s
+	for i := 1; i < len(table); i++ {
+		table[i] = *table[i-1].Add(s)
+	}
+	kb := k.Bytes()
+	var result, sum s256JacobianPoint
+	result.setInfinity()
+	for i := 63; i >= 0; i-- {
+		for d := 0; d < 4; d++ {
+			result.double(&result)
+		}
+		digit := uint64(kb[31-i/2]>>(4*uint(i%2))) & 0xf
+		var entry s256Point
+		var x, y s256FieldElement
+		x.Set(table[0].x)
+		y.Set(table[0].y)
+		for j := range table {
+			hit := ctEqual64(uint64(j+1), digit)
+			x.selectFrom(table[j].x, &x, hit)
+			y.selectFrom(table[j].y, &y, hit)
+		}
+		entry.x, entry.y = &x, &y
+		sum.addMixedConst(&result, &entry)
+		// a zero digit keeps the previous result.
+		nonZero := ctEqual64(digit, 0) ^ 1
+		result.x.selectFrom(&sum.x, &result.x, nonZero)
+		result.y.selectFrom(&sum.y, &result.y, nonZero)
+		result.z.selectFrom(&sum.z, &result.z, nonZero)
+	}
+	return newS256PointFromJacobian(&result)
+}
@@ -0,0 +1,109 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestECDH(t *testing.T) {
+	// act one of the BOLT8 handshake: es = ECDH(e, rs).
+	e := mustPrivateKeyFromHex(t, "1212121212121212121212121212121212121212121212121212121212121212")
+	rs, err := ParseSec(mustHex(t, "028d7500dd4c12685d1f568b4c2b5048e8534b873319f3a8daa612b469132ec7f7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ss, err := e.ECDH(rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(ss), "1e2fb3c8fe8fb9f262f649f64d26ecf0f2c0a805a767cf02dc2d77a6ef1fdcc3"; got != want {
+		t.Errorf("ECDH() = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		a, b *big.Int
+	}{
+		{big.NewInt(1), big.NewInt(2)},
+		{big.NewInt(0xdeadbeef), new(big.Int).Sub(genN(), big.NewInt(1))},
+		{mustBigFromHex("c28a9f80738f770d527803a566cf6fc3edf6cea586c4fc4a5223a5ad797e1ac3"), mustBigFromHex("0f")},
+	}
+	for _, tt := range tests {
+		a, _ := NewPrivateKey(tt.a)
+		b, _ := NewPrivateKey(tt.b)
+		ab, err := a.ECDH(b.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ba, err := b.ECDH(a.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ab, ba) {
+			t.Errorf("ECDH(%x, %x) = %x, but the other way round gives %x", tt.a, tt.b, ab, ba)
+		}
+
+		raw, err := a.ECDHRaw(b.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		refPriv, _ := btcec.PrivKeyFromBytes(btcec.S256(), tt.a.Bytes())
+		refPub, _ := btcec.ParsePubKey(b.p.Sec(true), btcec.S256())
+		if want := btcec.GenerateSharedSecret(refPriv, refPub); !bytes.Equal(raw, want) {
+			t.Errorf("ECDHRaw(%x, %x) = %x, want %x", tt.a, tt.b, raw, want)
+		}
+
+		custom, err := a.ECDHWithHash(b.p, func(x, y []byte) []byte {
+			h := sha512.Sum512(append(append([]byte{}, x...), y...))
+			return h[:]
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		shared := b.p.ScalarMul(&a.secret)
+		x, y := shared.x.Bytes(), shared.y.Bytes()
+		if want := sha512.Sum512(append(x[:], y[:]...)); !bytes.Equal(custom, want[:]) {
+			t.Errorf("ECDHWithHash(%x, %x) = %x, want %x", tt.a, tt.b, custom, want)
+		}
+	}
+}
+
+func TestECDHInvalidPoint(t *testing.T) {
+	p := mustPrivateKeyFromHex(t, "1212121212121212121212121212121212121212121212121212121212121212")
+	offCurve := &s256Point{newS256FieldElementFromUint64(1), newS256FieldElementFromUint64(1), genN()}
+	tests := []struct {
+		name string
+		pub  *s256Point
+		err  error
+	}{
+		{"nil", nil, ErrPubKeyInfinity},
+		{"infinity", S256Infinity(), ErrPubKeyInfinity},
+		{"off curve", offCurve, ErrPubKeyNotOnCurve},
+	}
+	for _, tt := range tests {
+		if _, err := p.ECDH(tt.pub); !errors.Is(err, tt.err) {
+			t.Errorf("%s: ECDH() error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestConstTimeMul(t *testing.T) {
+	g, _ := genG()
+	p := g.ScalarMul(NewScalar(7))
+	for _, k := range []*Scalar{
+		NewScalar(1),
+		NewScalar(15),
+		NewScalar(16),
+		new(Scalar).Negate(NewScalar(1)),
+		new(Scalar).Negate(NewScalar(0x10000)),
+	} {
+		if got, want := p.constTimeMul(k), p.ScalarMul(k); !got.Eq(want) {
+			t.Errorf("constTimeMul(%v) = %v, want %v", k.Big(), got, want)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package ecc
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/xerrors"
+)
+
+// ECDSA adaptor tags of secp256k1-zkp. The DLEQ nonce and challenge share
+// one tag there.
+const (
+	tagECDSAAdaptorNonce = "ECDSAadaptor/non"
+	tagECDSAAdaptorAux   = "ECDSAadaptor/aux"
+	tagDLEQ              = "DLEQ"
+)
+
+const ecdsaAdaptorSigLen = 33 + 33 + 32 + 64
+
+// ECDSAAdaptorSignature is an ECDSA pre-signature, or encrypted signature,
+// for an adaptor point Y = y*G in the construction of Fournier: R = k*Y,
+// R_a = k*G and s' = (z + r*x) / k with r the x of R. Dividing s' by y
+// gives the signature (r, s' / y). A proof that R and R_a share their
+// discrete log k ties it to Y.
+//
+// The nonces, the DLEQ proof and the encoding are those of the ecdsa_adaptor
+// module of secp256k1-zkp, which the DLC specification uses.
+type ECDSAAdaptorSignature struct {
+	r, ra *s256Point
+	s     Scalar
+	proof dleqProof
+}
+
+// Bytes returns the 162-byte encoding of sig: R and R_a compressed, s' and
+// the 64-byte proof (e, s).
+func (sig *ECDSAAdaptorSignature) Bytes() []byte {
+	b := append(sig.r.Sec(true), sig.ra.Sec(true)...)
+	s := sig.s.Bytes()
+	b = append(b, s[:]...)
+	return append(b, sig.proof.bytes()...)
+}
+
+// ParseECDSAAdaptorSignature decodes the 162-byte encoding of Bytes.
+func ParseECDSAAdaptorSignature(b []byte) (*ECDSAAdaptorSignature, error) {
+	if len(b) != ecdsaAdaptorSigLen {
+		return nil, xerrors.Errorf("got %d bytes, want %d: %w", len(b), ecdsaAdaptorSigLen, ErrInvalidAdaptorSigLength)
+	}
+	r, err := ParseSec(b[:33])
+	if err != nil {
+		return nil, xerrors.Errorf("R: %w", err)
+	}
+	ra, err := ParseSec(b[33:66])
+	if err != nil {
+		return nil, xerrors.Errorf("R_a: %w", err)
+	}
+	sig := &ECDSAAdaptorSignature{r: r, ra: ra}
+	if sig.s.SetByteSlice(b[66:98]) || sig.s.IsZero() {
+		return nil, xerrors.New("s is not in [1, n-1]")
+	}
+	if sig.proof, err = parseDLEQProof(b[98:]); err != nil {
+		return nil, err
+	}
+	return sig, nil
+}
+
+// SignECDSAAdaptor returns a pre-signature of the 32-byte hash for the
+// adaptor point Y. auxRand is nil or 32 bytes of randomness that is mixed
+// into the nonces, like the ndata of secp256k1_ecdsa_adaptor_encrypt.
+func (p *PrivateKey) SignECDSAAdaptor(hash []byte, adaptor *s256Point, auxRand []byte) (*ECDSAAdaptorSignature, error) {
+	if len(hash) != 32 {
+		return nil, xerrors.Errorf("hash must be 32 bytes, got %d", len(hash))
+	}
+	if err := validAdaptor(adaptor); err != nil {
+		return nil, err
+	}
+	k, err := ecdsaAdaptorNonce(tagECDSAAdaptorNonce, &p.secret, auxRand, adaptor.Sec(true), hash)
+	if err != nil {
+		return nil, err
+	}
+	sig := &ECDSAAdaptorSignature{
+		r:  adaptor.constTimeMul(&k),
+		ra: ScalarBaseMul(&k),
+	}
+	var r, z Scalar
+	r.SetBig(sig.r.x.Big())
+	z.SetBig(Secp256k1().hashToInt(hash))
+	if r.IsZero() {
+		return nil, xerrors.New("r is zero")
+	}
+	// s' = (z + r*x) / k
+	sig.s.Mul(&r, &p.secret).Add(&sig.s, &z).Mul(&sig.s, new(Scalar).Inverse(&k))
+	if sig.s.IsZero() {
+		return nil, xerrors.New("s is zero")
+	}
+	if sig.proof, err = newDLEQProof(&k, adaptor, sig.ra, sig.r, auxRand); err != nil {
+		return nil, err
+	}
+
+	if ok, err := p.p.VerifyECDSAAdaptor(hash, adaptor, sig); err != nil || !ok {
+		return nil, xerrors.New("created an invalid adaptor signature")
+	}
+	return sig, nil
+}
+
+// VerifyECDSAAdaptor reports whether sig is a pre-signature of hash by s
+// for the adaptor point Y: the proof must show that R = k*Y for the k of
+// R_a = k*G, and (z*G + r*P) / s' must be R_a.
+func (s *s256Point) VerifyECDSAAdaptor(hash []byte, adaptor *s256Point, sig *ECDSAAdaptorSignature) (bool, error) {
+	if s.IsInfinity() {
+		return false, xerrors.New("public key is infinity")
+	}
+	if err := validAdaptor(adaptor); err != nil {
+		return false, err
+	}
+	if !sig.proof.verify(adaptor, sig.ra, sig.r) {
+		return false, nil
+	}
+	var r, z Scalar
+	r.SetBig(sig.r.x.Big())
+	z.SetBig(Secp256k1().hashToInt(hash))
+	sInv := new(Scalar).inverseVar(&sig.s)
+	u := new(Scalar).Mul(&z, sInv)
+	v := new(Scalar).Mul(&r, sInv)
+	return DoubleScalarMul(u.Big(), v.Big(), s).Eq(sig.ra), nil
+}
+
+// Complete returns the low-S signature (r, s' / y), where y is the discrete
+// log of the adaptor point. It does not check y; a wrong y gives an
+// invalid signature.
+func (sig *ECDSAAdaptorSignature) Complete(y *Scalar) *Signature {
+	var r, s Scalar
+	r.SetBig(sig.r.x.Big())
+	s.Mul(&sig.s, new(Scalar).Inverse(y))
+	if s.IsHigh() {
+		s.Negate(&s)
+	}
+	return NewSignature(r.Big(), s.Big())
+}
+
+// Extract returns the discrete log y of the adaptor point from sig and the
+// signature that completed it, which may have had its s negated. It
+// returns ErrAdaptorMismatch if final was not made from sig for that
+// adaptor point.
+func (sig *ECDSAAdaptorSignature) Extract(final *Signature, adaptor *s256Point) (*Scalar, error) {
+	if err := validAdaptor(adaptor); err != nil {
+		return nil, err
+	}
+	var r, s Scalar
+	r.SetBig(sig.r.x.Big())
+	if fr := new(Scalar); fr.SetBig(final.r) || !fr.Equal(&r) {
+		return nil, xerrors.Errorf("r differs: %w", ErrAdaptorMismatch)
+	}
+	if s.SetBig(final.s) || s.IsZero() {
+		return nil, xerrors.Errorf("s is not in [1, n-1]: %w", ErrAdaptorMismatch)
+	}
+	// y = s' / s, or its negation if s was negated.
+	y := new(Scalar).Mul(&sig.s, new(Scalar).inverseVar(&s))
+	Y := ScalarBaseMul(y)
+	switch {
+	case Y.Eq(adaptor):
+	case Y.Eq(adaptor.Negate()):
+		y.Negate(y)
+	default:
+		return nil, xerrors.Errorf("y*G is not the adaptor point: %w", ErrAdaptorMismatch)
+	}
+	return y, nil
+}
+
+// ecdsaAdaptorNonce is nonce_function_ecdsa_adaptor of secp256k1-zkp: the
+// tagged hash of the key, masked with the hash of auxRand if there is one,
+// the 33-byte point and the 32-byte message.
+func ecdsaAdaptorNonce(tag string, key *Scalar, auxRand, point, msg []byte) (Scalar, error) {
+	var k Scalar
+	t := key.Bytes()
+	if auxRand != nil {
+		if len(auxRand) != 32 {
+			return k, xerrors.Errorf("aux randomness must be 32 bytes, got %d", len(auxRand))
+		}
+		aux := taggedHash(tagECDSAAdaptorAux, auxRand)
+		for i := range t {
+			t[i] ^= aux[i]
+		}
+	}
+	h := taggedHash(tag, t[:], point, msg)
+	k.SetBytes(&h)
+	if k.IsZero() {
+		return k, xerrors.New("nonce is zero")
+	}
+	return k, nil
+}
+
+// dleqProof is a Chaum-Pedersen proof (e, z) that A = k*G and B = k*Y for
+// one k.
+type dleqProof struct {
+	e, z Scalar
+}
+
+func (p dleqProof) bytes() []byte {
+	e, z := p.e.Bytes(), p.z.Bytes()
+	return append(e[:], z[:]...)
+}
+
+// parseDLEQProof decodes (e, z). Like secp256k1-zkp it reduces e, which is
+// only compared with a hash, but requires z to be below n.
+func parseDLEQProof(b []byte) (dleqProof, error) {
+	var p dleqProof
+	p.e.SetByteSlice(b[:32])
+	if p.z.SetByteSlice(b[32:64]) {
+		return p, xerrors.New("DLEQ proof is not below n")
+	}
+	return p, nil
+}
+
+// dleqChallenge returns e = hash_DLEQ(A || Y || B || A' || B') mod n.
+func dleqChallenge(y, a, b, a1, b1 *s256Point) (Scalar, bool) {
+	var e Scalar
+	if a1.IsInfinity() || b1.IsInfinity() {
+		return e, false
+	}
+	h := taggedHash(tagDLEQ, a.Sec(true), y.Sec(true), b.Sec(true), a1.Sec(true), b1.Sec(true))
+	e.SetBytes(&h)
+	return e, true
+}
+
+// newDLEQProof proves that A = k*G and B = k*Y. Its nonce is derived from
+// k, Y and the hash of A and B with the nonce function of the signature,
+// so it is deterministic like k itself.
+func newDLEQProof(k *Scalar, y, a, b *s256Point, auxRand []byte) (dleqProof, error) {
+	var p dleqProof
+	ab := sha256.Sum256(append(a.Sec(true), b.Sec(true)...))
+	nonce, err := ecdsaAdaptorNonce(tagDLEQ, k, auxRand, y.Sec(true), ab[:])
+	if err != nil {
+		return p, xerrors.Errorf("DLEQ: %w", err)
+	}
+	e, ok := dleqChallenge(y, a, b, ScalarBaseMul(&nonce), y.constTimeMul(&nonce))
+	if !ok {
+		return p, xerrors.New("DLEQ commitment is infinity")
+	}
+	// z = nonce + e*k
+	p.e = e
+	p.z.Mul(&e, k).Add(&p.z, &nonce)
+	return p, nil
+}
+
+// verify reports whether p proves that A and B have the same discrete log
+// to the bases G and Y: with A' = z*G - e*A and B' = z*Y - e*B the
+// challenge must be e.
+func (p dleqProof) verify(y, a, b *s256Point) bool {
+	negE := new(Scalar).Negate(&p.e)
+	a1 := DoubleScalarMul(p.z.Big(), negE.Big(), a)
+	b1 := y.ScalarMul(&p.z).Add(b.ScalarMul(negE))
+	e, ok := dleqChallenge(y, a, b, a1, b1)
+	return ok && e.Equal(&p.e)
+}
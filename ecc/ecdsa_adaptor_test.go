@@ -0,0 +1,227 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestECDSAAdaptor(t *testing.T) {
+	keys := []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9",
+	}
+	for _, key := range keys {
+		p := mustPrivateKeyFromHex(t, key)
+		for _, secret := range keys {
+			adaptorSecret := mustPrivateKeyFromHex(t, secret)
+			Y := adaptorSecret.p
+			for m := 0; m < 4; m++ {
+				hash := sha256.Sum256([]byte(fmt.Sprintf("contract execution %d", m)))
+				pre, err := p.SignECDSAAdaptor(hash[:], Y, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				parsed, err := ParseECDSAAdaptorSignature(pre.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok, err := p.p.VerifyECDSAAdaptor(hash[:], Y, parsed); err != nil || !ok {
+					t.Fatalf("VerifyECDSAAdaptor() = %v, %v, want true", ok, err)
+				}
+				sig := parsed.Complete(&adaptorSecret.secret)
+				if ok, err := p.p.Verify(new(big.Int).SetBytes(hash[:]), *sig); err != nil || !ok {
+					t.Errorf("completed signature by %s does not verify", key)
+				}
+				refPub, _ := btcec.ParsePubKey(p.p.Sec(true), btcec.S256())
+				if ref := (&btcec.Signature{R: sig.r, S: sig.s}); !ref.Verify(hash[:], refPub) {
+					t.Errorf("btcec rejects the completed signature by %s", key)
+				}
+				got, err := pre.Extract(sig, Y)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(&adaptorSecret.secret) {
+					t.Errorf("Extract() = %x, want %s", got.Bytes(), secret)
+				}
+			}
+		}
+	}
+}
+
+func TestECDSAAdaptorInvalid(t *testing.T) {
+	p := mustPrivateKeyFromHex(t, "c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9")
+	adaptorSecret := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	Y := adaptorSecret.p
+	hash := sha256.Sum256([]byte("contract execution"))
+	pre, err := p.SignECDSAAdaptor(hash[:], Y, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000002")
+	otherHash := sha256.Sum256([]byte("another contract execution"))
+
+	// R = k*Y with a k different from that of R_a fails the proof even
+	// though everything else matches.
+	forged := *pre
+	forged.r = Y.ScalarMul(NewScalar(5))
+
+	tests := []struct {
+		name string
+		pub  *s256Point
+		hash []byte
+		Y    *s256Point
+		sig  *ECDSAAdaptorSignature
+	}{
+		{"other key", other.p, hash[:], Y, pre},
+		{"other hash", p.p, otherHash[:], Y, pre},
+		{"other adaptor", p.p, hash[:], other.p, pre},
+		{"forged R", p.p, hash[:], Y, &forged},
+	}
+	for _, tt := range tests {
+		if ok, err := tt.pub.VerifyECDSAAdaptor(tt.hash, tt.Y, tt.sig); err != nil || ok {
+			t.Errorf("%s: VerifyECDSAAdaptor() = %v, %v, want false", tt.name, ok, err)
+		}
+	}
+	if _, err := p.SignECDSAAdaptor(hash[:], nil, nil); !errors.Is(err, ErrPubKeyInfinity) {
+		t.Errorf("SignECDSAAdaptor(nil) error = %v, want %v", err, ErrPubKeyInfinity)
+	}
+
+	unrelated, err := p.SignHash(hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pre.Extract(unrelated, Y); !errors.Is(err, ErrAdaptorMismatch) {
+		t.Errorf("Extract(unrelated) error = %v, want %v", err, ErrAdaptorMismatch)
+	}
+	sig := pre.Complete(&adaptorSecret.secret)
+	if _, err := pre.Extract(sig, other.p); !errors.Is(err, ErrAdaptorMismatch) {
+		t.Errorf("Extract(other adaptor) error = %v, want %v", err, ErrAdaptorMismatch)
+	}
+	if _, err := ParseECDSAAdaptorSignature(pre.Bytes()[1:]); !errors.Is(err, ErrInvalidAdaptorSigLength) {
+		t.Errorf("ParseECDSAAdaptorSignature(161 bytes) error = %v, want %v", err, ErrInvalidAdaptorSigLength)
+	}
+}
+
+func TestECDSAAdaptorVectors(t *testing.T) {
+	// the first test vector of the ECDSA adaptor signatures of the DLC
+	// specification, which secp256k1-zkp checks as well.
+	pre := mustHex(t, "03424d14a5471c048ab87b3b83f6085d125d5864249ae4297a57c84e74710bb673"+
+		"02"+"23f325042fce535d040fee52ec13231bf709ccd84233c6944b90317e62528b25"+
+		"27dff9d659a96db4c99f9750168308633c1867b70f3a18fb0f4539a1aecedcd1"+
+		"fc0148fc22f36b6303083ece3f872b18e35d368b3958efe5fb081f7716736ccb"+
+		"598d269aa3084d57e1855e1ea9a45efc10463bbf32ae378029f5763ceb40173f")
+	hash := mustHex(t, "8131e6f4b45754f2c90bd06688ceeabc0c45055460729928b4eecf11026a9e2d")
+	pub, err := ParseSec(mustHex(t, "035be5e9478209674a96e60f1f037f6176540fd001fa1d64694770c56a7709c42c"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	Y, err := ParseSec(mustHex(t, "02c2662c97488b07b6e819124b8989849206334a4c2fbdf691f7b34d2b16e9c293"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var y Scalar
+	y.SetByteSlice(mustHex(t, "0b2aba63b885a0f0e96fa0f303920c7fb7431ddfa94376ad94d969fbf4109dc8"))
+	wantR := "424d14a5471c048ab87b3b83f6085d125d5864249ae4297a57c84e74710bb673"
+	wantS := "29e80e0ee60e57af3e625bbae1672b1ecaa58effe613426b024fa1621d903394"
+
+	sig, err := ParseECDSAAdaptorSignature(pre)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := sig.Bytes(); !bytes.Equal(got, pre) {
+		t.Errorf("Bytes() = %x, want %x", got, pre)
+	}
+	if ok, err := pub.VerifyECDSAAdaptor(hash, Y, sig); err != nil || !ok {
+		t.Fatalf("VerifyECDSAAdaptor() = %v, %v, want true", ok, err)
+	}
+	final := sig.Complete(&y)
+	if r, s := fmt.Sprintf("%064x", final.r), fmt.Sprintf("%064x", final.s); r != wantR || s != wantS {
+		t.Errorf("Complete() = r:%s s:%s, want r:%s s:%s", r, s, wantR, wantS)
+	}
+	got, err := sig.Extract(final, Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(&y) {
+		t.Errorf("Extract() = %x, want %x", got.Bytes(), y.Bytes())
+	}
+
+	g, _ := genG()
+	otherHash := sha256.Sum256(hash)
+	mutate := func(i int) []byte {
+		b := append([]byte{}, pre...)
+		b[i] ^= 0x01
+		return b
+	}
+	tests := []struct {
+		name string
+		pre  []byte
+		hash []byte
+		Y    *s256Point
+	}{
+		{name: "other hash", pre: pre, hash: otherHash[:], Y: Y},
+		{name: "other adaptor", pre: pre, hash: hash, Y: g},
+		{name: "changed s'", pre: mutate(97), hash: hash, Y: Y},
+		{name: "changed proof e", pre: mutate(129), hash: hash, Y: Y},
+		{name: "changed proof s", pre: mutate(161), hash: hash, Y: Y},
+	}
+	for _, tt := range tests {
+		sig, err := ParseECDSAAdaptorSignature(tt.pre)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := pub.VerifyECDSAAdaptor(tt.hash, tt.Y, sig); err != nil || ok {
+			t.Errorf("%s: VerifyECDSAAdaptor() = %v, %v, want false", tt.name, ok, err)
+		}
+	}
+}
+
+func TestSignECDSAAdaptor_regression(t *testing.T) {
+	// the nonce is not covered by the vectors of the DLC specification, so
+	// these values generated by this implementation pin it.
+	tests := []struct {
+		secret, adaptorSecret, aux, hash string
+		pre, sig                         string
+	}{
+		{
+			secret:        "c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9",
+			adaptorSecret: "0b432b2677937381aef05bb02a66ecd012773062cf3fa2549e44f58ed2401710",
+			aux:           "0000000000000000000000000000000000000000000000000000000000000001",
+			hash:          "e5fab60c661434574c147ff244fe0b8f514d9ddb34986dbe860291bba6de9789",
+			pre: "0262e0a1586fbf9667d654e1f7b6115db1b39c9544656d576ec98051d4a3fe92f1" +
+				"0379948887efef8e36963322e6779918ee9c7019c6f7d87f08cabf2d83a44f538e" +
+				"842fb04eecf8989f99fad41eaddd0914436b0d59beb0de3db469ec9ba43ef010" +
+				"273dffac1faeb35f4a0e0eed628d8f124eb61bb0f80b1175c5854de7698212c6" +
+				"a3f951221091312064ca0f2d51cc88d9b3652d650f2d79f64d243864f49b64ec",
+			sig: "3044022062e0a1586fbf9667d654e1f7b6115db1b39c9544656d576ec98051d4a3fe92f1" +
+				"022064bcd1c4e1a54c82b340ce7ee58dac4370841de73a1c85ead128907e30bd1dfe",
+		},
+	}
+	for _, tt := range tests {
+		p := mustPrivateKeyFromHex(t, tt.secret)
+		y := mustPrivateKeyFromHex(t, tt.adaptorSecret)
+		hash := mustHex(t, tt.hash)
+		pre, err := p.SignECDSAAdaptor(hash, y.p, mustHex(t, tt.aux))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(pre.Bytes()); got != tt.pre {
+			t.Errorf("SignECDSAAdaptor() = %s, want %s", got, tt.pre)
+		}
+		sig := pre.Complete(&y.secret)
+		if got := hex.EncodeToString(sig.Der()); got != tt.sig {
+			t.Errorf("Complete() = %s, want %s", got, tt.sig)
+		}
+		if ok, err := p.p.Verify(new(big.Int).SetBytes(hash), *sig); err != nil || !ok {
+			t.Errorf("%s does not verify", tt.sig)
+		}
+	}
+}
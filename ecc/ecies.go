@@ -0,0 +1,240 @@
+package ecc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+	"golang.org/x/xerrors"
+)
+
+// ECIESCipher is the AEAD that encrypts the message under the derived key.
+type ECIESCipher int
+
+const (
+	// ECIESAES256GCM is AES-256-GCM, the default of eciespy.
+	ECIESAES256GCM ECIESCipher = iota
+	// ECIESXChaCha20Poly1305 is XChaCha20-Poly1305 with a 24-byte nonce,
+	// the "xchacha20" cipher of eciespy.
+	ECIESXChaCha20Poly1305
+)
+
+// ECIESOptions selects one of the layouts eciespy can be configured for.
+// The format has no version byte of its own, as eciespy has none, so both
+// sides must agree on the options; EncryptVersioned records the layout in
+// the ciphertext instead. The zero value is the eciespy default:
+// an uncompressed ephemeral key, uncompressed points in the key derivation
+// and AES-256-GCM with a 16-byte nonce.
+type ECIESOptions struct {
+	Cipher ECIESCipher
+	// CompressedEphemeralKey is is_ephemeral_key_compressed of eciespy.
+	CompressedEphemeralKey bool
+	// CompressedHKDFKey is is_hkdf_key_compressed of eciespy.
+	CompressedHKDFKey bool
+	// ShortNonce uses the standard 12-byte nonce of AES-GCM instead of 16
+	// bytes, symmetric_nonce_length = 12 in eciespy. XChaCha20-Poly1305
+	// always uses 24 bytes.
+	ShortNonce bool
+}
+
+var defaultECIESOptions ECIESOptions
+
+// ECIESVersion names a fixed set of ECIESOptions. EncryptVersioned writes
+// it as one byte in front of the eciespy payload, so that the layout can
+// change later without breaking old ciphertexts. The versions are never
+// redefined; a new layout gets a new version.
+type ECIESVersion byte
+
+const (
+	// ECIESVersion1 is the eciespy default: an uncompressed ephemeral key,
+	// uncompressed points in the key derivation and AES-256-GCM with a
+	// 16-byte nonce.
+	ECIESVersion1 ECIESVersion = 1
+	// ECIESVersion2 uses compressed points in both places and
+	// XChaCha20-Poly1305.
+	ECIESVersion2 ECIESVersion = 2
+)
+
+// options returns the layout of v, or nil for an unknown version.
+func (v ECIESVersion) options() *ECIESOptions {
+	switch v {
+	case ECIESVersion1:
+		return &ECIESOptions{}
+	case ECIESVersion2:
+		return &ECIESOptions{Cipher: ECIESXChaCha20Poly1305, CompressedEphemeralKey: true, CompressedHKDFKey: true}
+	}
+	return nil
+}
+
+// EncryptVersioned encrypts msg to pub with the layout of version and
+// returns version || payload, where payload is what EncryptWithOptions
+// returns for that layout. Without the first byte the payload is what
+// eciespy produces with the same configuration.
+func EncryptVersioned(pub *s256Point, msg []byte, version ECIESVersion, rand io.Reader) ([]byte, error) {
+	opts := version.options()
+	if opts == nil {
+		return nil, xerrors.Errorf("version %d: %w", version, ErrECIESVersion)
+	}
+	payload, err := EncryptWithOptions(pub, msg, opts, rand)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(version)}, payload...), nil
+}
+
+// DecryptVersioned decrypts a ciphertext made by EncryptVersioned, using
+// the layout its first byte names. It returns ErrECIESVersion for an
+// unknown version and ErrECIESDecrypt if the payload does not
+// authenticate.
+func DecryptVersioned(p *PrivateKey, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) == 0 {
+		return nil, xerrors.Errorf("empty ciphertext: %w", ErrECIESDecrypt)
+	}
+	version := ECIESVersion(ciphertext[0])
+	opts := version.options()
+	if opts == nil {
+		return nil, xerrors.Errorf("version %d: %w", version, ErrECIESVersion)
+	}
+	return DecryptWithOptions(p, ciphertext[1:], opts)
+}
+
+// Encrypt encrypts msg to pub with the default options. The ciphertext is
+// ephemeral public key || nonce || tag || encrypted message, where the
+// AEAD key is HKDF-SHA256 of the ephemeral public key followed by the
+// shared point, with no salt and no info.
+func Encrypt(pub *s256Point, msg []byte) ([]byte, error) {
+	return EncryptWithOptions(pub, msg, nil, nil)
+}
+
+// EncryptWithOptions is Encrypt with the layout chosen by opts, or the
+// default when it is nil. The ephemeral key and the nonce are read from
+// rand, which defaults to crypto/rand.
+func EncryptWithOptions(pub *s256Point, msg []byte, opts *ECIESOptions, rand io.Reader) ([]byte, error) {
+	if opts == nil {
+		opts = &defaultECIESOptions
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	ephemeral, err := newPrivateKeyFromScalar(&secret)
+	if err != nil {
+		return nil, err
+	}
+	key, err := eciesKey(ephemeral, pub, ephemeral.p, opts.CompressedHKDFKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := opts.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	out := ephemeral.p.Sec(opts.CompressedEphemeralKey)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand, nonce); err != nil {
+		return nil, xerrors.Errorf("reading randomness: %w", err)
+	}
+	out = append(out, nonce...)
+	// Seal appends the tag, which eciespy puts before the encrypted message.
+	sealed := aead.Seal(nil, nonce, msg, nil)
+	tag := sealed[len(msg):]
+	out = append(out, tag...)
+	return append(out, sealed[:len(msg)]...), nil
+}
+
+// Decrypt decrypts a ciphertext made by Encrypt for the public key of p.
+func Decrypt(p *PrivateKey, ciphertext []byte) ([]byte, error) {
+	return DecryptWithOptions(p, ciphertext, nil)
+}
+
+// DecryptWithOptions decrypts a ciphertext made by EncryptWithOptions with
+// the same options. It returns ErrECIESDecrypt if the ciphertext does not
+// authenticate.
+func DecryptWithOptions(p *PrivateKey, ciphertext []byte, opts *ECIESOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &defaultECIESOptions
+	}
+	keyLen := 65
+	if opts.CompressedEphemeralKey {
+		keyLen = 33
+	}
+	nonceLen := opts.nonceSize()
+	if len(ciphertext) < keyLen+nonceLen+16 {
+		return nil, xerrors.Errorf("ciphertext is %d bytes: %w", len(ciphertext), ErrECIESDecrypt)
+	}
+	ephemeral, err := ParseSec(ciphertext[:keyLen])
+	if err != nil {
+		return nil, xerrors.Errorf("ephemeral public key: %w", err)
+	}
+	key, err := eciesKey(p, ephemeral, ephemeral, opts.CompressedHKDFKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := opts.aead(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[keyLen : keyLen+nonceLen]
+	tag := ciphertext[keyLen+nonceLen : keyLen+nonceLen+16]
+	sealed := append(append([]byte{}, ciphertext[keyLen+nonceLen+16:]...), tag...)
+	msg, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("%v: %w", err, ErrECIESDecrypt)
+	}
+	return msg, nil
+}
+
+// eciesKey returns the AEAD key HKDF-SHA256(E || p*pub), where E is the
+// ephemeral public key and both points are SEC encoded.
+func eciesKey(p *PrivateKey, pub, ephemeral *s256Point, compressed bool) ([]byte, error) {
+	shared, err := p.ECDHWithHash(pub, func(x, y []byte) []byte {
+		if compressed {
+			return append([]byte{0x02 | y[31]&1}, x...)
+		}
+		return append(append([]byte{0x04}, x...), y...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eciesHKDF(append(ephemeral.Sec(compressed), shared...))
+}
+
+// eciesHKDF derives a 32-byte key from master with HKDF-SHA256.
+func eciesHKDF(master []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, master, nil, nil), key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func (o *ECIESOptions) nonceSize() int {
+	switch {
+	case o.Cipher == ECIESXChaCha20Poly1305:
+		return chacha20poly1305.NonceSizeX
+	case o.ShortNonce:
+		return 12
+	}
+	return 16
+}
+
+func (o *ECIESOptions) aead(key []byte) (cipher.AEAD, error) {
+	switch o.Cipher {
+	case ECIESAES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCMWithNonceSize(block, o.nonceSize())
+	case ECIESXChaCha20Poly1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, xerrors.Errorf("unknown ECIES cipher %d", o.Cipher)
+}
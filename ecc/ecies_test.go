@@ -0,0 +1,158 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestECIESKey(t *testing.T) {
+	// from the tests of eciespy.
+	key, err := eciesHKDF([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := hex.EncodeToString(key), "2f34e5ff91ec85d53ca9b543683174d0cf550b60d5f52b24c97b386cfcf6cbbf"; got != want {
+		t.Errorf("eciesHKDF(secret) = %s, want %s", got, want)
+	}
+	k1 := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000002")
+	k2 := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	enc, err := eciesKey(k1, k2.p, k1.p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := eciesKey(k2, k1.p, k1.p, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "6f982d63e8590c9d9b5b4c1959ff80315d772edd8f60287c9361d548d5200f82"
+	if got := hex.EncodeToString(enc); got != want {
+		t.Errorf("encapsulate = %s, want %s", got, want)
+	}
+	if got := hex.EncodeToString(dec); got != want {
+		t.Errorf("decapsulate = %s, want %s", got, want)
+	}
+}
+
+func TestECIES(t *testing.T) {
+	p := mustPrivateKeyFromHex(t, "c28a9f80738f770d527803a566cf6fc3edf6cea586c4fc4a5223a5ad797e1ac3")
+	other := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	msg := []byte("a backup of the wallet descriptors")
+	tests := []struct {
+		name string
+		opts *ECIESOptions
+		// length of ephemeral key, nonce and tag.
+		overhead int
+	}{
+		{"default", nil, 65 + 16 + 16},
+		{"compressed ephemeral key", &ECIESOptions{CompressedEphemeralKey: true}, 33 + 16 + 16},
+		{"compressed hkdf key", &ECIESOptions{CompressedHKDFKey: true}, 65 + 16 + 16},
+		{"short nonce", &ECIESOptions{ShortNonce: true}, 65 + 12 + 16},
+		{"xchacha20", &ECIESOptions{Cipher: ECIESXChaCha20Poly1305, CompressedEphemeralKey: true}, 33 + 24 + 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct, err := EncryptWithOptions(p.p, msg, tt.opts, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ct) != tt.overhead+len(msg) {
+				t.Errorf("ciphertext is %d bytes, want %d", len(ct), tt.overhead+len(msg))
+			}
+			got, err := DecryptWithOptions(p, ct, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, msg) {
+				t.Errorf("DecryptWithOptions() = %q, want %q", got, msg)
+			}
+			if _, err := DecryptWithOptions(other, ct, tt.opts); !errors.Is(err, ErrECIESDecrypt) {
+				t.Errorf("decrypting with another key: error = %v, want %v", err, ErrECIESDecrypt)
+			}
+			for _, i := range []int{tt.overhead - 1, len(ct) - 1} {
+				tampered := append([]byte{}, ct...)
+				tampered[i] ^= 1
+				if _, err := DecryptWithOptions(p, tampered, tt.opts); !errors.Is(err, ErrECIESDecrypt) {
+					t.Errorf("flipping byte %d: error = %v, want %v", i, err, ErrECIESDecrypt)
+				}
+			}
+			if _, err := DecryptWithOptions(p, ct[:tt.overhead-1], tt.opts); !errors.Is(err, ErrECIESDecrypt) {
+				t.Errorf("truncated: error = %v, want %v", err, ErrECIESDecrypt)
+			}
+		})
+	}
+
+	// the default layout is E || nonce || tag || ciphertext.
+	ct, err := Encrypt(p.p, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ephemeral, err := ParseSec(ct[:65])
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := eciesKey(p, ephemeral, ephemeral, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := aes.NewCipher(key)
+	gcm, _ := cipher.NewGCMWithNonceSize(block, 16)
+	got, err := gcm.Open(nil, ct[65:81], append(append([]byte{}, ct[97:]...), ct[81:97]...), nil)
+	if err != nil || !bytes.Equal(got, msg) {
+		t.Errorf("AES-GCM of the default layout = %q, %v, want %q", got, err, msg)
+	}
+	if got, err := Decrypt(p, ct); err != nil || !bytes.Equal(got, msg) {
+		t.Errorf("Decrypt() = %q, %v, want %q", got, err, msg)
+	}
+}
+
+func TestECIESVersioned(t *testing.T) {
+	p := mustPrivateKeyFromHex(t, "c28a9f80738f770d527803a566cf6fc3edf6cea586c4fc4a5223a5ad797e1ac3")
+	msg := []byte("a backup of the wallet descriptors")
+	tests := []struct {
+		version ECIESVersion
+		opts    *ECIESOptions
+	}{
+		{ECIESVersion1, nil},
+		{ECIESVersion2, &ECIESOptions{Cipher: ECIESXChaCha20Poly1305, CompressedEphemeralKey: true, CompressedHKDFKey: true}},
+	}
+	for _, tt := range tests {
+		ct, err := EncryptVersioned(p.p, msg, tt.version, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ECIESVersion(ct[0]) != tt.version {
+			t.Errorf("version byte = %d, want %d", ct[0], tt.version)
+		}
+		if got, err := DecryptVersioned(p, ct); err != nil || !bytes.Equal(got, msg) {
+			t.Errorf("DecryptVersioned(%d) = %q, %v, want %q", tt.version, got, err, msg)
+		}
+		// the rest is the unversioned eciespy payload.
+		if got, err := DecryptWithOptions(p, ct[1:], tt.opts); err != nil || !bytes.Equal(got, msg) {
+			t.Errorf("DecryptWithOptions() of version %d = %q, %v, want %q", tt.version, got, err, msg)
+		}
+		// a different version byte selects another layout.
+		other := append([]byte{}, ct...)
+		other[0] = byte(ECIESVersion1 + ECIESVersion2 - tt.version)
+		if _, err := DecryptVersioned(p, other); err == nil {
+			t.Errorf("DecryptVersioned() of version %d relabelled succeeded", tt.version)
+		}
+	}
+	if _, err := EncryptVersioned(p.p, msg, 0, nil); !errors.Is(err, ErrECIESVersion) {
+		t.Errorf("EncryptVersioned(0) error = %v, want %v", err, ErrECIESVersion)
+	}
+	ct, err := EncryptVersioned(p.p, msg, ECIESVersion1, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ct[0] = 0xff
+	if _, err := DecryptVersioned(p, ct); !errors.Is(err, ErrECIESVersion) {
+		t.Errorf("DecryptVersioned() with version 0xff error = %v, want %v", err, ErrECIESVersion)
+	}
+	if _, err := DecryptVersioned(p, nil); !errors.Is(err, ErrECIESDecrypt) {
+		t.Errorf("DecryptVersioned(nil) error = %v, want %v", err, ErrECIESDecrypt)
+	}
+}
@@ -0,0 +1,94 @@
+package ecc
+
+import (
+	"crypto/elliptic"
+	"math/big"
+	"sync"
+)
+
+// s256Curve exposes the s256Point arithmetic as an elliptic.Curve, so that
+// crypto/ecdsa and anything else written against the standard library can
+// work with secp256k1 keys.
+type s256Curve struct {
+	params *elliptic.CurveParams
+}
+
+var (
+	s256CurveOnce sync.Once
+	s256CurveImpl *s256Curve
+)
+
+// S256 returns an elliptic.Curve which implements secp256k1. Points use the
+// elliptic package convention that (0, 0) is the point at infinity.
+//
+// ScalarBaseMult reads the whole generator table for every window and is
+// safe to use with secret scalars. ScalarMult runs in variable time.
+func S256() elliptic.Curve {
+	s256CurveOnce.Do(func() {
+		c := Secp256k1()
+		s256CurveImpl = &s256Curve{&elliptic.CurveParams{
+			P:       c.P,
+			N:       c.N,
+			B:       c.B,
+			Gx:      c.G.x.number,
+			Gy:      c.G.y.number,
+			BitSize: 256,
+			Name:    c.Name,
+		}}
+	})
+	return s256CurveImpl
+}
+
+func (c *s256Curve) Params() *elliptic.CurveParams {
+	return c.params
+}
+
+func (c *s256Curve) IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || y.Sign() < 0 {
+		return false
+	}
+	_, err := NewS256Point(x, y)
+	return err == nil
+}
+
+// point converts (x, y) to an s256Point. Like the standard library curves,
+// it panics on points that are not on the curve.
+func (c *s256Curve) point(x, y *big.Int) *s256Point {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return S256Infinity()
+	}
+	if !c.IsOnCurve(x, y) {
+		panic("ecc: invalid secp256k1 point")
+	}
+	p, _ := NewS256Point(x, y)
+	return p
+}
+
+// affine converts p back to the elliptic package representation.
+func (c *s256Curve) affine(p *s256Point) (x, y *big.Int) {
+	if p.IsInfinity() {
+		return new(big.Int), new(big.Int)
+	}
+	return p.x.Big(), p.y.Big()
+}
+
+func (c *s256Curve) Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return c.affine(c.point(x1, y1).Add(c.point(x2, y2)))
+}
+
+func (c *s256Curve) Double(x1, y1 *big.Int) (x, y *big.Int) {
+	p := c.point(x1, y1)
+	return c.affine(p.Add(p))
+}
+
+func (c *s256Curve) ScalarMult(x1, y1 *big.Int, k []byte) (x, y *big.Int) {
+	var s Scalar
+	s.SetBig(new(big.Int).SetBytes(k))
+	return c.affine(c.point(x1, y1).ScalarMul(&s))
+}
+
+func (c *s256Curve) ScalarBaseMult(k []byte) (x, y *big.Int) {
+	var s Scalar
+	s.SetBig(new(big.Int).SetBytes(k))
+	return c.affine(ScalarBaseMul(&s))
+}
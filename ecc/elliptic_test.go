@@ -0,0 +1,148 @@
+package ecc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestS256_matchesBtcec(t *testing.T) {
+	var curve elliptic.Curve = S256()
+	ref := btcec.S256()
+	scalars := [][]byte{
+		{0x01},
+		{0x02},
+		mustGetFromHex("0x1e240").Bytes(),
+		mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140").Bytes(),
+		mustGetFromHex("0xdeadbeef12345").Bytes(),
+	}
+	for _, k := range scalars {
+		x, y := curve.ScalarBaseMult(k)
+		wantX, wantY := ref.ScalarBaseMult(k)
+		if x.Cmp(wantX) != 0 || y.Cmp(wantY) != 0 {
+			t.Fatalf("ScalarBaseMult(%x) = (%x, %x), want (%x, %x)", k, x, y, wantX, wantY)
+		}
+		if !curve.IsOnCurve(x, y) {
+			t.Fatalf("ScalarBaseMult(%x) is not on the curve", k)
+		}
+		x2, y2 := curve.ScalarMult(x, y, k)
+		wantX2, wantY2 := ref.ScalarMult(wantX, wantY, k)
+		if x2.Cmp(wantX2) != 0 || y2.Cmp(wantY2) != 0 {
+			t.Fatalf("ScalarMult(%x) mismatch", k)
+		}
+		x3, y3 := curve.Add(x, y, x2, y2)
+		wantX3, wantY3 := ref.Add(wantX, wantY, wantX2, wantY2)
+		if x3.Cmp(wantX3) != 0 || y3.Cmp(wantY3) != 0 {
+			t.Fatalf("Add(%x) mismatch", k)
+		}
+		x4, y4 := curve.Double(x, y)
+		wantX4, wantY4 := ref.Double(wantX, wantY)
+		if x4.Cmp(wantX4) != 0 || y4.Cmp(wantY4) != 0 {
+			t.Fatalf("Double(%x) mismatch", k)
+		}
+	}
+}
+
+func TestS256_infinity(t *testing.T) {
+	curve := S256()
+	params := curve.Params()
+	x, y := curve.ScalarBaseMult(params.N.Bytes())
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("n*G = (%x, %x), want (0, 0)", x, y)
+	}
+	x, y = curve.Add(params.Gx, params.Gy, new(big.Int), new(big.Int))
+	if x.Cmp(params.Gx) != 0 || y.Cmp(params.Gy) != 0 {
+		t.Errorf("G + infinity = (%x, %x), want G", x, y)
+	}
+	x, y = curve.Add(params.Gx, params.Gy, params.Gx, new(big.Int).Sub(params.P, params.Gy))
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("G - G = (%x, %x), want (0, 0)", x, y)
+	}
+	if curve.IsOnCurve(new(big.Int), new(big.Int)) {
+		t.Error("(0, 0) must not be reported as on the curve")
+	}
+}
+
+func TestS256_ecdsa(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(12345))
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := p.ToECDSA()
+	hash := sha256.Sum256([]byte("crypto/ecdsa"))
+	der, err := ecdsa.SignASN1(rand.Reader, priv, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := p.Public().(*PublicKey)
+	if !ecdsa.VerifyASN1(pub, hash[:], der) {
+		t.Fatal("signature made through S256 does not verify")
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, priv, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := p.p.Verify(new(big.Int).SetBytes(hash[:]), *NewSignature(r, s)); !ok {
+		t.Error("s256Point.Verify rejects a crypto/ecdsa signature")
+	}
+}
+
+func TestPrivateKey_Signer(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signer crypto.Signer = p
+	hash := sha256.Sum256([]byte("test message"))
+	got, err := signer.Sign(nil, hash[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := p.SignHash(hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want.Der()) {
+		t.Errorf("Sign() = %x, want %x", got, want.Der())
+	}
+	if !ecdsa.VerifyASN1(p.Public().(*PublicKey), hash[:], got) {
+		t.Error("crypto/ecdsa rejects the crypto.Signer signature")
+	}
+	if _, err := signer.Sign(nil, hash[:20], crypto.SHA256); err == nil {
+		t.Error("Sign() accepted a digest of the wrong size")
+	}
+}
+
+func TestPublicKeyPoint(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := p.Public().(*PublicKey)
+	if !pub.Equal(p.p.ToECDSA()) {
+		t.Error("Public() is not equal to ToECDSA()")
+	}
+	got, err := PublicKeyPoint(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Eq(p.p) {
+		t.Error("PublicKeyPoint() does not round trip")
+	}
+	p256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err == nil {
+		if _, err := PublicKeyPoint(&p256.PublicKey); err == nil {
+			t.Error("PublicKeyPoint() accepted a P-256 key")
+		}
+	}
+	bad := &PublicKey{Curve: S256(), X: big.NewInt(1), Y: big.NewInt(1)}
+	if _, err := PublicKeyPoint(bad); err == nil {
+		t.Error("PublicKeyPoint() accepted a point off the curve")
+	}
+}
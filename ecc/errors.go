@@ -0,0 +1,106 @@
+package ecc
+
+import (
+	"fmt"
+
+	"golang.org/x/xerrors"
+)
+
+// Errors returned by the parsers. They are wrapped with more context, so
+// match them with errors.Is.
+var (
+	// ErrInvalidPubKeyLength means a SEC encoding does not have the length
+	// its prefix calls for.
+	ErrInvalidPubKeyLength = xerrors.New("invalid public key length")
+	// ErrInvalidPubKeyPrefix means a SEC encoding starts with a byte other
+	// than 0x02, 0x03 or 0x04.
+	ErrInvalidPubKeyPrefix = xerrors.New("invalid public key prefix")
+	// ErrPubKeyNotOnCurve means the encoded coordinates are not a point on
+	// the curve, or are not smaller than the field prime.
+	ErrPubKeyNotOnCurve = xerrors.New("public key is not on the curve")
+	// ErrPubKeyInfinity means a public key is the point at infinity, which
+	// has no encoding and no private key.
+	ErrPubKeyInfinity = xerrors.New("public key is the point at infinity")
+
+	// ErrEvenModulus means a Legendre symbol or square root was asked for
+	// modulo an even number, where neither is defined.
+	ErrEvenModulus = xerrors.New("modulus is even")
+
+	// ErrInvalidCompactSigLength means a compact signature is not 65 bytes.
+	ErrInvalidCompactSigLength = xerrors.New("invalid compact signature length")
+	// ErrInvalidRecoveryID means a compact signature header or recovery id
+	// is out of range, or names an R that cannot exist.
+	ErrInvalidRecoveryID = xerrors.New("invalid recovery id")
+
+	// ErrInvalidSchnorrSigLength means a BIP340 signature is not 64 bytes.
+	ErrInvalidSchnorrSigLength = xerrors.New("invalid schnorr signature length")
+	// ErrSchnorrSigRange means the r of a BIP340 signature is not below the
+	// field prime or its s is not below the group order.
+	ErrSchnorrSigRange = xerrors.New("schnorr signature value out of range")
+
+	// ErrInvalidAdaptorSigLength means a Schnorr or ECDSA adaptor signature
+	// does not have the length of its encoding.
+	ErrInvalidAdaptorSigLength = xerrors.New("invalid adaptor signature length")
+	// ErrAdaptorMismatch means a signature is not the completion of an
+	// adaptor signature for the given adaptor point, so no secret can be
+	// extracted from the pair.
+	ErrAdaptorMismatch = xerrors.New("signature does not complete the adaptor signature")
+
+	// ErrDERTruncated means a DER signature ends before the lengths it
+	// declares.
+	ErrDERTruncated = xerrors.New("DER signature is truncated")
+	// ErrDERInvalidHeader means a DER signature does not start with the
+	// SEQUENCE tag 0x30.
+	ErrDERInvalidHeader = xerrors.New("DER signature has no SEQUENCE header")
+	// ErrDERInvalidLength means the lengths inside a DER signature do not
+	// add up to its size.
+	ErrDERInvalidLength = xerrors.New("DER signature has an inconsistent length")
+	// ErrDERInvalidIntMarker means r or s is not tagged as an INTEGER 0x02.
+	ErrDERInvalidIntMarker = xerrors.New("DER signature has a bad INTEGER marker")
+	// ErrDERZeroLength means r or s is encoded with no bytes at all.
+	ErrDERZeroLength = xerrors.New("DER signature has an empty INTEGER")
+	// ErrDERNegativeInt means r or s has its sign bit set, which BIP66
+	// forbids.
+	ErrDERNegativeInt = xerrors.New("DER signature has a negative INTEGER")
+	// ErrDERExcessPadding means r or s starts with a zero byte that is not
+	// needed to keep it positive, which BIP66 forbids.
+	ErrDERExcessPadding = xerrors.New("DER signature has an INTEGER with excess padding")
+
+	// ErrInvalidTweak means a tweak is not below n, or turns a key into zero
+	// or infinity. BIP32 skips to the next child index when this happens.
+	ErrInvalidTweak = xerrors.New("invalid tweak")
+
+	// ErrECIESDecrypt means an ECIES ciphertext is truncated or does not
+	// authenticate under the private key.
+	ErrECIESDecrypt = xerrors.New("ECIES decryption failed")
+	// ErrECIESVersion means a versioned ECIES ciphertext names a version
+	// this package does not know.
+	ErrECIESVersion = xerrors.New("unknown ECIES version")
+
+	// ErrBIP322Unsupported means a BIP322 signature or address needs script
+	// features this package does not implement, so the result is
+	// inconclusive rather than invalid.
+	ErrBIP322Unsupported = xerrors.New("BIP322 signature is not supported")
+)
+
+// ErrNonceUsed means a MuSig2 or FROST session was asked to sign a second
+// time. Its secret nonce is erased by the first attempt, successful or not,
+// because signing twice with one nonce reveals the private key.
+var ErrNonceUsed = xerrors.New("secret nonce has already been used")
+
+// InvalidContributionError blames a signer for an invalid public key,
+// nonce, share or signature, so that the others can exclude it. Signer is
+// the index of the signer for MuSig2, as BIP327 requires, and the
+// participant identifier for FROST.
+type InvalidContributionError struct {
+	Signer int
+	Err    error
+}
+
+func (e *InvalidContributionError) Error() string {
+	return fmt.Sprintf("invalid contribution from signer %d: %v", e.Signer, e.Err)
+}
+
+func (e *InvalidContributionError) Unwrap() error {
+	return e.Err
+}
@@ -18,6 +18,15 @@ func NewFieldElement(number, prime *big.Int) (*fieldElement, error) {
 	return &fieldElement{number: number, prime: prime}, nil
 }
 
+// clone returns a copy of f whose number can be changed independently. The
+// prime is never modified by any operation, so it is shared. A nil f stays nil.
+func (f *fieldElement) clone() *fieldElement {
+	if f == nil {
+		return nil
+	}
+	return &fieldElement{new(big.Int).Set(f.number), f.prime}
+}
+
 func (f *fieldElement) Equal(other *fieldElement) bool {
 	if other == nil {
 		return false
@@ -69,3 +78,121 @@ func (f *fieldElement) Div(x, y *fieldElement) (*fieldElement, error) {
 	f.number.Mod(pow, x.prime)
 	return f, nil
 }
+
+// NewFieldElementFromBytes decodes the big-endian number b as an element of
+// F_prime. b may be of any length, but the number must be smaller than prime.
+func NewFieldElementFromBytes(b []byte, prime *big.Int) (*fieldElement, error) {
+	return NewFieldElement(new(big.Int).SetBytes(b), prime)
+}
+
+// Bytes returns the big-endian encoding of f, padded to the byte length of
+// the prime.
+func (f *fieldElement) Bytes() []byte {
+	return f.number.FillBytes(make([]byte, (f.prime.BitLen()+7)/8))
+}
+
+// Cmp compares the numbers of f and other as integers in [0, prime) and
+// returns -1, 0 or +1.
+func (f *fieldElement) Cmp(other *fieldElement) int {
+	return f.number.Cmp(other.number)
+}
+
+// IsZero reports whether f is zero.
+func (f *fieldElement) IsZero() bool {
+	return f.number.Sign() == 0
+}
+
+// Neg sets f to -x mod prime and returns f.
+func (f *fieldElement) Neg(x *fieldElement) *fieldElement {
+	f.number.Mod(f.number.Neg(x.number), x.prime)
+	return f
+}
+
+// Inverse sets f to the multiplicative inverse of x and returns f. Zero has
+// no inverse and gives an error.
+func (f *fieldElement) Inverse(x *fieldElement) (*fieldElement, error) {
+	if x.IsZero() {
+		return nil, xerrors.New("zero has no inverse")
+	}
+	f.number.ModInverse(x.number, x.prime)
+	return f, nil
+}
+
+// Legendre returns the Legendre symbol of f: 0 if f is zero, 1 if it is a
+// non-zero square and -1 otherwise. It is only defined for an odd prime.
+func (f *fieldElement) Legendre() (int, error) {
+	if f.prime.Bit(0) == 0 {
+		return 0, xerrors.Errorf("prime %v: %w", f.prime, ErrEvenModulus)
+	}
+	return big.Jacobi(f.number, f.prime), nil
+}
+
+// IsSquare reports whether f has a square root, counting zero as a square.
+func (f *fieldElement) IsSquare() (bool, error) {
+	l, err := f.Legendre()
+	if err != nil {
+		return false, err
+	}
+	return l >= 0, nil
+}
+
+// Sqrt sets f to a square root of x. It fails if x is not a square. Primes
+// with p = 3 mod 4 take a single exponentiation; any other odd prime goes
+// through Tonelli-Shanks.
+func (f *fieldElement) Sqrt(x *fieldElement) (*fieldElement, error) {
+	square, err := x.IsSquare()
+	if err != nil {
+		return nil, err
+	}
+	if !square {
+		return nil, xerrors.Errorf("%v is not a square modulo %v", x.number, x.prime)
+	}
+	p := x.prime
+	if x.IsZero() {
+		f.number.SetInt64(0)
+		return f, nil
+	}
+	one := big.NewInt(1)
+	if p.Bit(0) == 1 && p.Bit(1) == 1 {
+		// y = x**((p + 1) / 4)
+		exp := new(big.Int).Add(p, one)
+		f.number.Exp(x.number, exp.Rsh(exp, 2), p)
+		return f, nil
+	}
+	// p - 1 = q * 2**s with q odd.
+	q := new(big.Int).Sub(p, one)
+	s := 0
+	for q.Bit(0) == 0 {
+		q.Rsh(q, 1)
+		s++
+	}
+	// z is any non-square.
+	z := big.NewInt(2)
+	for big.Jacobi(z, p) != -1 {
+		z.Add(z, one)
+	}
+	m := s
+	c := new(big.Int).Exp(z, q, p)
+	t := new(big.Int).Exp(x.number, q, p)
+	// r = x**((q + 1) / 2)
+	exp := new(big.Int).Add(q, one)
+	r := new(big.Int).Exp(x.number, exp.Rsh(exp, 1), p)
+	for t.Cmp(one) != 0 {
+		// find the least i with t**(2**i) = 1.
+		i := 0
+		for t2 := new(big.Int).Set(t); t2.Cmp(one) != 0; i++ {
+			t2.Mul(t2, t2).Mod(t2, p)
+		}
+		// b = c**(2**(m - i - 1))
+		b := new(big.Int).Set(c)
+		for j := 0; j < m-i-1; j++ {
+			b.Mul(b, b).Mod(b, p)
+		}
+		m = i
+		c.Mul(b, b).Mod(c, p)
+		t.Mul(t, c).Mod(t, p)
+		r.Mul(r, b).Mod(r, p)
+	}
+	f.number.Set(r)
+	return f, nil
+}
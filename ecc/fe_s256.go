@@ -0,0 +1,294 @@
+package ecc
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// s256FieldElement is an element of the secp256k1 base field held as four
+// little-endian 64-bit limbs. Values are always fully reduced modulo p and
+// every operation runs in constant time with respect to the operands.
+type s256FieldElement [4]uint64
+
+// p = 2**256 - 2**32 - 977
+var s256P = s256FieldElement{
+	0xfffffffefffffc2f,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+	0xffffffffffffffff,
+}
+
+// 2**256 mod p, used to fold the upper half of a product into the lower half.
+const s256PComplement = 0x1000003d1
+
+func newS256FieldElementFromUint64(v uint64) *s256FieldElement {
+	return &s256FieldElement{v, 0, 0, 0}
+}
+
+// SetBytes sets f to the big-endian value b reduced modulo p and reports
+// whether b was not less than p.
+func (f *s256FieldElement) SetBytes(b *[32]byte) (overflow bool) {
+	for i := 0; i < 4; i++ {
+		f[i] = uint64(b[31-8*i]) | uint64(b[30-8*i])<<8 | uint64(b[29-8*i])<<16 |
+			uint64(b[28-8*i])<<24 | uint64(b[27-8*i])<<32 | uint64(b[26-8*i])<<40 |
+			uint64(b[25-8*i])<<48 | uint64(b[24-8*i])<<56
+	}
+	return f.reduce() == 1
+}
+
+// SetBig sets f to number modulo p and reports whether number was outside
+// the range [0, p).
+func (f *s256FieldElement) SetBig(number *big.Int) (overflow bool) {
+	var b [32]byte
+	if number.Sign() < 0 || number.BitLen() > 256 {
+		new(big.Int).Mod(number, s256P.Big()).FillBytes(b[:])
+		f.SetBytes(&b)
+		return true
+	}
+	number.FillBytes(b[:])
+	return f.SetBytes(&b)
+}
+
+// Bytes returns the 32-byte big-endian encoding of f.
+func (f *s256FieldElement) Bytes() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			b[31-8*i-j] = byte(f[i] >> (8 * j))
+		}
+	}
+	return b
+}
+
+// Big returns f as a new big.Int.
+func (f *s256FieldElement) Big() *big.Int {
+	b := f.Bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// reduce subtracts p once if f >= p and returns 1 when it did so.
+func (f *s256FieldElement) reduce() uint64 {
+	var t s256FieldElement
+	var borrow uint64
+	t[0], borrow = bits.Sub64(f[0], s256P[0], 0)
+	t[1], borrow = bits.Sub64(f[1], s256P[1], borrow)
+	t[2], borrow = bits.Sub64(f[2], s256P[2], borrow)
+	t[3], borrow = bits.Sub64(f[3], s256P[3], borrow)
+	f.selectFrom(&t, f, borrow^1)
+	return borrow ^ 1
+}
+
+// selectFrom sets f to a when cond is 1 and to b when cond is 0.
+func (f *s256FieldElement) selectFrom(a, b *s256FieldElement, cond uint64) *s256FieldElement {
+	mask := -cond
+	f[0] = (a[0] & mask) | (b[0] &^ mask)
+	f[1] = (a[1] & mask) | (b[1] &^ mask)
+	f[2] = (a[2] & mask) | (b[2] &^ mask)
+	f[3] = (a[3] & mask) | (b[3] &^ mask)
+	return f
+}
+
+// Set sets f to x.
+func (f *s256FieldElement) Set(x *s256FieldElement) *s256FieldElement {
+	*f = *x
+	return f
+}
+
+// Equal reports whether f and other hold the same value.
+func (f *s256FieldElement) Equal(other *s256FieldElement) bool {
+	if other == nil {
+		return false
+	}
+	d := (f[0] ^ other[0]) | (f[1] ^ other[1]) | (f[2] ^ other[2]) | (f[3] ^ other[3])
+	return d == 0
+}
+
+// IsZero reports whether f is zero.
+func (f *s256FieldElement) IsZero() bool {
+	return (f[0] | f[1] | f[2] | f[3]) == 0
+}
+
+// ctIsZero returns 1 if f is zero and 0 otherwise without branching.
+func (f *s256FieldElement) ctIsZero() uint64 {
+	return ctEqual64(f[0]|f[1]|f[2]|f[3], 0)
+}
+
+// IsOdd reports whether the canonical value of f is odd.
+func (f *s256FieldElement) IsOdd() bool {
+	return f[0]&1 == 1
+}
+
+// Add sets f = x + y mod p.
+func (f *s256FieldElement) Add(x, y *s256FieldElement) *s256FieldElement {
+	var sum, t s256FieldElement
+	var carry, borrow uint64
+	sum[0], carry = bits.Add64(x[0], y[0], 0)
+	sum[1], carry = bits.Add64(x[1], y[1], carry)
+	sum[2], carry = bits.Add64(x[2], y[2], carry)
+	sum[3], carry = bits.Add64(x[3], y[3], carry)
+
+	t[0], borrow = bits.Sub64(sum[0], s256P[0], 0)
+	t[1], borrow = bits.Sub64(sum[1], s256P[1], borrow)
+	t[2], borrow = bits.Sub64(sum[2], s256P[2], borrow)
+	t[3], borrow = bits.Sub64(sum[3], s256P[3], borrow)
+
+	// the subtraction is kept when the sum overflowed 2**256 or did not borrow.
+	return f.selectFrom(&t, &sum, carry|(borrow^1))
+}
+
+// Sub sets f = x - y mod p.
+func (f *s256FieldElement) Sub(x, y *s256FieldElement) *s256FieldElement {
+	var d s256FieldElement
+	var borrow, carry uint64
+	d[0], borrow = bits.Sub64(x[0], y[0], 0)
+	d[1], borrow = bits.Sub64(x[1], y[1], borrow)
+	d[2], borrow = bits.Sub64(x[2], y[2], borrow)
+	d[3], borrow = bits.Sub64(x[3], y[3], borrow)
+
+	mask := -borrow
+	f[0], carry = bits.Add64(d[0], s256P[0]&mask, 0)
+	f[1], carry = bits.Add64(d[1], s256P[1]&mask, carry)
+	f[2], carry = bits.Add64(d[2], s256P[2]&mask, carry)
+	f[3], _ = bits.Add64(d[3], s256P[3]&mask, carry)
+	return f
+}
+
+// Neg sets f = -x mod p.
+func (f *s256FieldElement) Neg(x *s256FieldElement) *s256FieldElement {
+	var zero s256FieldElement
+	return f.Sub(&zero, x)
+}
+
+// Mul sets f = x * y mod p.
+func (f *s256FieldElement) Mul(x, y *s256FieldElement) *s256FieldElement {
+	// schoolbook multiplication into the 512-bit t0..t7.
+	c, t0 := madd64(x[0], y[0], 0, 0)
+	c, t1 := madd64(x[0], y[1], 0, c)
+	c, t2 := madd64(x[0], y[2], 0, c)
+	t4, t3 := madd64(x[0], y[3], 0, c)
+
+	c, t1 = madd64(x[1], y[0], t1, 0)
+	c, t2 = madd64(x[1], y[1], t2, c)
+	c, t3 = madd64(x[1], y[2], t3, c)
+	t5, t4 := madd64(x[1], y[3], t4, c)
+
+	c, t2 = madd64(x[2], y[0], t2, 0)
+	c, t3 = madd64(x[2], y[1], t3, c)
+	c, t4 = madd64(x[2], y[2], t4, c)
+	t6, t5 := madd64(x[2], y[3], t5, c)
+
+	c, t3 = madd64(x[3], y[0], t3, 0)
+	c, t4 = madd64(x[3], y[1], t4, c)
+	c, t5 = madd64(x[3], y[2], t5, c)
+	t7, t6 := madd64(x[3], y[3], t6, c)
+
+	return f.reduce512(t0, t1, t2, t3, t4, t5, t6, t7)
+}
+
+// madd64 returns a*b + c + d as a 128-bit value, which cannot overflow.
+func madd64(a, b, c, d uint64) (hi, lo uint64) {
+	hi, lo = bits.Mul64(a, b)
+	var carry uint64
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return hi, lo
+}
+
+// Square sets f = x * x mod p.
+func (f *s256FieldElement) Square(x *s256FieldElement) *s256FieldElement {
+	return f.Mul(x, x)
+}
+
+// squareN sets f = x**(2**n) mod p.
+func (f *s256FieldElement) squareN(x *s256FieldElement, n int) *s256FieldElement {
+	f.Square(x)
+	for i := 1; i < n; i++ {
+		f.Square(f)
+	}
+	return f
+}
+
+// reduce512 sets f to the 512-bit little-endian value t0..t7 reduced modulo
+// p, using 2**256 = 0x1000003d1 mod p twice to fold the upper limbs.
+func (f *s256FieldElement) reduce512(t0, t1, t2, t3, t4, t5, t6, t7 uint64) *s256FieldElement {
+	c, r0 := madd64(t4, s256PComplement, t0, 0)
+	c, r1 := madd64(t5, s256PComplement, t1, c)
+	c, r2 := madd64(t6, s256PComplement, t2, c)
+	r4, r3 := madd64(t7, s256PComplement, t3, c)
+
+	hi, lo := bits.Mul64(r4, s256PComplement)
+	var carry uint64
+	r0, carry = bits.Add64(r0, lo, 0)
+	r1, carry = bits.Add64(r1, hi, carry)
+	r2, carry = bits.Add64(r2, 0, carry)
+	r3, carry = bits.Add64(r3, 0, carry)
+
+	// a final carry is worth another 2**256 = 0x1000003d1 mod p; the value
+	// left below 2**256 is small enough that this cannot carry again.
+	r0, carry = bits.Add64(r0, s256PComplement&(-carry), 0)
+	r1, carry = bits.Add64(r1, 0, carry)
+	r2, carry = bits.Add64(r2, 0, carry)
+	r3, _ = bits.Add64(r3, 0, carry)
+
+	f[0], f[1], f[2], f[3] = r0, r1, r2, r3
+	f.reduce()
+	return f
+}
+
+// powBlocks computes the powers x**(2**k - 1) that the inversion and square
+// root addition chains are built from. The chains are the ones used by
+// libsecp256k1 and only depend on p, never on x.
+func (f *s256FieldElement) powBlocks(x *s256FieldElement) (x2, x3, x22, x223 s256FieldElement) {
+	var x6, x9, x11, x44, x88, x176, x220 s256FieldElement
+	x2.Square(x)
+	x2.Mul(&x2, x)
+	x3.Square(&x2)
+	x3.Mul(&x3, x)
+	x6.squareN(&x3, 3).Mul(&x6, &x3)
+	x9.squareN(&x6, 3).Mul(&x9, &x3)
+	x11.squareN(&x9, 2).Mul(&x11, &x2)
+	x22.squareN(&x11, 11).Mul(&x22, &x11)
+	x44.squareN(&x22, 22).Mul(&x44, &x22)
+	x88.squareN(&x44, 44).Mul(&x88, &x44)
+	x176.squareN(&x88, 88).Mul(&x176, &x88)
+	x220.squareN(&x176, 44).Mul(&x220, &x44)
+	x223.squareN(&x220, 3).Mul(&x223, &x3)
+	return x2, x3, x22, x223
+}
+
+// Inverse sets f = x**-1 mod p by raising x to p - 2. The inverse of zero is
+// zero.
+func (f *s256FieldElement) Inverse(x *s256FieldElement) *s256FieldElement {
+	in := *x
+	x2, _, x22, x223 := f.powBlocks(&in)
+	// p - 2 has blocks of 1s of lengths 223, 22, 1, 2 and 1.
+	var t s256FieldElement
+	t.squareN(&x223, 23).Mul(&t, &x22)
+	t.squareN(&t, 5).Mul(&t, &in)
+	t.squareN(&t, 3).Mul(&t, &x2)
+	t.squareN(&t, 2).Mul(&t, &in)
+	*f = t
+	return f
+}
+
+// Sqrt sets f to a square root of x and reports whether x is a quadratic
+// residue. Since p = 3 mod 4 the root is x**((p+1)/4); when x is not a
+// residue f is left holding that power anyway.
+func (f *s256FieldElement) Sqrt(x *s256FieldElement) bool {
+	in := *x
+	x2, _, x22, x223 := f.powBlocks(&in)
+	// (p + 1) / 4 has blocks of 1s of lengths 223, 22 and 2.
+	var t s256FieldElement
+	t.squareN(&x223, 23).Mul(&t, &x22)
+	t.squareN(&t, 6).Mul(&t, &x2)
+	f.squareN(&t, 2)
+	var check s256FieldElement
+	check.Square(f)
+	return check.Equal(&in)
+}
+
+func (f s256FieldElement) String() string {
+	return f.Big().Text(16)
+}
@@ -0,0 +1,126 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func s256FieldTestValues() []*big.Int {
+	prime := genPrime()
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(7),
+		new(big.Int).Sub(prime, big.NewInt(1)),
+		new(big.Int).Sub(prime, big.NewInt(2)),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		new(big.Int).Sub(prime, new(big.Int).Lsh(big.NewInt(1), 64)),
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		values = append(values, new(big.Int).Rand(r, prime))
+	}
+	return values
+}
+
+func Test_s256FieldElement_SetBytes(t *testing.T) {
+	tests := []struct {
+		name         string
+		number       *big.Int
+		want         *big.Int
+		wantOverflow bool
+	}{
+		{
+			name:   "Ok if less than prime",
+			number: big.NewInt(5),
+			want:   big.NewInt(5),
+		},
+		{
+			name:         "Overflow if equal to prime",
+			number:       genPrime(),
+			want:         big.NewInt(0),
+			wantOverflow: true,
+		},
+		{
+			name:         "Overflow if larger than prime",
+			number:       new(big.Int).Add(genPrime(), big.NewInt(3)),
+			want:         big.NewInt(3),
+			wantOverflow: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b [32]byte
+			tt.number.FillBytes(b[:])
+			f := new(s256FieldElement)
+			if got := f.SetBytes(&b); got != tt.wantOverflow {
+				t.Errorf("s256FieldElement.SetBytes() overflow = %v, want %v", got, tt.wantOverflow)
+			}
+			if f.Big().Cmp(tt.want) != 0 {
+				t.Errorf("s256FieldElement.SetBytes() = %v, want %v", f.Big(), tt.want)
+			}
+		})
+	}
+}
+
+func Test_s256FieldElement_Arithmetic(t *testing.T) {
+	prime := genPrime()
+	values := s256FieldTestValues()
+	for i, x := range values {
+		y := values[(i*7+3)%len(values)]
+		fx, fy := mustS256Field(x), mustS256Field(y)
+
+		want := new(big.Int).Add(x, y)
+		want.Mod(want, prime)
+		if got := new(s256FieldElement).Add(fx, fy).Big(); got.Cmp(want) != 0 {
+			t.Errorf("s256FieldElement.Add(%x, %x) = %x, want %x", x, y, got, want)
+		}
+		want = new(big.Int).Sub(x, y)
+		want.Mod(want, prime)
+		if got := new(s256FieldElement).Sub(fx, fy).Big(); got.Cmp(want) != 0 {
+			t.Errorf("s256FieldElement.Sub(%x, %x) = %x, want %x", x, y, got, want)
+		}
+		want = new(big.Int).Neg(x)
+		want.Mod(want, prime)
+		if got := new(s256FieldElement).Neg(fx).Big(); got.Cmp(want) != 0 {
+			t.Errorf("s256FieldElement.Neg(%x) = %x, want %x", x, got, want)
+		}
+		want = new(big.Int).Mul(x, y)
+		want.Mod(want, prime)
+		if got := new(s256FieldElement).Mul(fx, fy).Big(); got.Cmp(want) != 0 {
+			t.Errorf("s256FieldElement.Mul(%x, %x) = %x, want %x", x, y, got, want)
+		}
+		want = new(big.Int).Mul(x, x)
+		want.Mod(want, prime)
+		if got := new(s256FieldElement).Square(fx).Big(); got.Cmp(want) != 0 {
+			t.Errorf("s256FieldElement.Square(%x) = %x, want %x", x, got, want)
+		}
+		if x.Sign() != 0 {
+			want = new(big.Int).ModInverse(x, prime)
+			if got := new(s256FieldElement).Inverse(fx).Big(); got.Cmp(want) != 0 {
+				t.Errorf("s256FieldElement.Inverse(%x) = %x, want %x", x, got, want)
+			}
+		}
+	}
+}
+
+func Test_s256FieldElement_Sqrt(t *testing.T) {
+	prime := genPrime()
+	for _, x := range s256FieldTestValues() {
+		fx := mustS256Field(x)
+		root := new(s256FieldElement)
+		ok := root.Sqrt(fx)
+		want := new(big.Int).ModSqrt(x, prime) != nil
+		if ok != want {
+			t.Errorf("s256FieldElement.Sqrt(%x) ok = %v, want %v", x, ok, want)
+			continue
+		}
+		if ok {
+			if got := new(s256FieldElement).Square(root); !got.Equal(fx) {
+				t.Errorf("s256FieldElement.Sqrt(%x)**2 = %v", x, got)
+			}
+		}
+	}
+}
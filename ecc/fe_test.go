@@ -1,6 +1,7 @@
 package ecc
 
 import (
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
@@ -261,3 +262,160 @@ func Test_fieldElement_Div(t *testing.T) {
 		})
 	}
 }
+
+func Test_fieldElement_Neg(t *testing.T) {
+	tests := []struct {
+		name string
+		self *fieldElement
+		want *big.Int
+	}{
+		{
+			name: "Ok if it called with proper value",
+			self: &fieldElement{number: big.NewInt(3), prime: big.NewInt(31)},
+			want: big.NewInt(28),
+		},
+		{
+			name: "Ok if it called with zero",
+			self: &fieldElement{number: big.NewInt(0), prime: big.NewInt(31)},
+			want: big.NewInt(0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.self.Neg(tt.self)
+			if tt.self.number.Cmp(tt.want) != 0 {
+				t.Errorf("fieldElement.Neg() result = %v, want %v", tt.self.number, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fieldElement_Inverse(t *testing.T) {
+	tests := []struct {
+		name    string
+		self    *fieldElement
+		want    *big.Int
+		wantErr bool
+	}{
+		{
+			name: "Ok if it called with proper value",
+			self: &fieldElement{number: big.NewInt(24), prime: big.NewInt(31)},
+			want: big.NewInt(22),
+		},
+		{
+			name:    "NG if it called with zero",
+			self:    &fieldElement{number: big.NewInt(0), prime: big.NewInt(31)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.self.Inverse(tt.self)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fieldElement.Inverse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && tt.self.number.Cmp(tt.want) != 0 {
+				t.Errorf("fieldElement.Inverse() result = %v, want %v", tt.self.number, tt.want)
+			}
+		})
+	}
+}
+
+func Test_fieldElement_Sqrt(t *testing.T) {
+	tests := []struct {
+		name  string
+		prime *big.Int
+	}{
+		// p = 3 mod 4
+		{name: "F223", prime: big.NewInt(223)},
+		// p = 5 mod 8
+		{name: "F13", prime: big.NewInt(13)},
+		// p - 1 = 3 * 2**5
+		{name: "F97", prime: big.NewInt(97)},
+		// p - 1 = 2**4
+		{name: "F17", prime: big.NewInt(17)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			squares := map[int64]bool{}
+			for i := int64(0); i < tt.prime.Int64(); i++ {
+				squares[i*i%tt.prime.Int64()] = true
+			}
+			for i := int64(0); i < tt.prime.Int64(); i++ {
+				x := &fieldElement{big.NewInt(i), tt.prime}
+				if got, err := x.IsSquare(); err != nil || got != squares[i] {
+					t.Fatalf("fieldElement.IsSquare(%d) = %v, %v, want %v", i, got, err, squares[i])
+				}
+				y, err := x.clone().Sqrt(x)
+				if (err != nil) == squares[i] {
+					t.Fatalf("fieldElement.Sqrt(%d) error = %v, square %v", i, err, squares[i])
+				}
+				if err != nil {
+					continue
+				}
+				if y2, _ := y.clone().Mul(y, y); !y2.Equal(x) {
+					t.Errorf("fieldElement.Sqrt(%d) = %v, which squares to %v", i, y.number, y2.number)
+				}
+			}
+		})
+	}
+}
+
+func Test_fieldElement_Sqrt_large(t *testing.T) {
+	primes := map[string]*big.Int{
+		"secp256k1": Secp256k1().P,
+		"secp256r1": Secp256r1().P,
+		// p - 1 is divisible by 2**96.
+		"secp224r1": mustGetFromHex("0xffffffffffffffffffffffffffffffff000000000000000000000001"),
+	}
+	for name, prime := range primes {
+		t.Run(name, func(t *testing.T) {
+			for i := int64(1); i < 50; i++ {
+				r := new(big.Int).Exp(big.NewInt(i), big.NewInt(77), prime)
+				x := &fieldElement{new(big.Int).Mul(r, r), prime}
+				x.number.Mod(x.number, prime)
+				if l, err := x.Legendre(); err != nil || l != 1 {
+					t.Fatalf("fieldElement.Legendre() = %d, %v, want 1", l, err)
+				}
+				y, err := x.clone().Sqrt(x)
+				if err != nil {
+					t.Fatal(err)
+				}
+				want := &fieldElement{r, prime}
+				if y.Cmp(want) != 0 && y.Cmp(want.clone().Neg(want)) != 0 {
+					t.Errorf("fieldElement.Sqrt() = %x, want +-%x", y.number, r)
+				}
+			}
+		})
+	}
+}
+
+func Test_fieldElement_Sqrt_evenModulus(t *testing.T) {
+	x := &fieldElement{big.NewInt(4), big.NewInt(16)}
+	if _, err := x.Legendre(); !errors.Is(err, ErrEvenModulus) {
+		t.Errorf("fieldElement.Legendre() error = %v, want %v", err, ErrEvenModulus)
+	}
+	if _, err := x.clone().Sqrt(x); !errors.Is(err, ErrEvenModulus) {
+		t.Errorf("fieldElement.Sqrt() error = %v, want %v", err, ErrEvenModulus)
+	}
+}
+
+func Test_fieldElement_Bytes(t *testing.T) {
+	prime := Secp256k1().P
+	f, err := NewFieldElementFromBytes([]byte{0x01, 0x02}, prime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := f.Bytes()
+	if len(b) != 32 || b[30] != 0x01 || b[31] != 0x02 {
+		t.Errorf("fieldElement.Bytes() = %x", b)
+	}
+	if _, err := NewFieldElementFromBytes(prime.Bytes(), prime); err == nil {
+		t.Error("NewFieldElementFromBytes() should return error but nil")
+	}
+	small := &fieldElement{big.NewInt(5), big.NewInt(223)}
+	if got := small.Bytes(); len(got) != 1 || got[0] != 5 {
+		t.Errorf("fieldElement.Bytes() = %x, want 05", got)
+	}
+}
@@ -0,0 +1,268 @@
+package ecc
+
+import (
+	"io"
+	"sort"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// FrostCommitment is the round one message of a FROST signer: the hiding
+// and binding nonce points D_i = d_i*G and E_i = e_i*G.
+type FrostCommitment struct {
+	ID      uint32
+	Hiding  *s256Point
+	Binding *s256Point
+}
+
+// frostNonces are the secret nonces d_i and e_i behind a commitment.
+type frostNonces struct {
+	hiding, binding Scalar
+}
+
+// FrostSession is one participant's part in a single FROST signing. Like
+// MuSig2Session it keeps the secret nonces to itself and erases them on
+// the first call to Sign, so they cannot be used twice.
+type FrostSession struct {
+	key        *FrostKeyShare
+	commitment *FrostCommitment
+
+	mu     sync.Mutex
+	nonces *frostNonces
+}
+
+// NewSigningSession generates the nonces for one signature and returns the
+// session. rand defaults to crypto/rand; the nonces also depend on the
+// secret share, so a weak rand does not reveal it on its own.
+func (k *FrostKeyShare) NewSigningSession(rand io.Reader) (*FrostSession, error) {
+	n := &frostNonces{}
+	for _, nonce := range []*Scalar{&n.hiding, &n.binding} {
+		r, err := randomScalar(rand)
+		if err != nil {
+			return nil, err
+		}
+		rb := r.Bytes()
+		sb := k.secret.Bytes()
+		h := taggedHash(tagFrostNonce, rb[:], sb[:])
+		if nonce.SetBytes(&h); nonce.IsZero() {
+			return nil, xerrors.New("nonce is zero")
+		}
+	}
+	return &FrostSession{
+		key: k,
+		commitment: &FrostCommitment{
+			ID:      k.id,
+			Hiding:  ScalarBaseMul(&n.hiding),
+			Binding: ScalarBaseMul(&n.binding),
+		},
+		nonces: n,
+	}, nil
+}
+
+// Commitment returns the round one message to send to the coordinator.
+func (s *FrostSession) Commitment() *FrostCommitment {
+	c := *s.commitment
+	return &c
+}
+
+// frostSigningValues are the values every signer and the aggregator derive
+// from the group, the message and the commitments of the signing set.
+type frostSigningValues struct {
+	group       *FrostGroup
+	commitments []*FrostCommitment
+	rho         map[uint32]Scalar
+	r           *s256Point
+	c           Scalar
+}
+
+func newFrostSigningValues(group *FrostGroup, msg []byte, commitments []*FrostCommitment) (*frostSigningValues, error) {
+	if len(commitments) < group.threshold {
+		return nil, xerrors.Errorf("%d signers are fewer than the threshold %d", len(commitments), group.threshold)
+	}
+	// every entry is checked before sorting, which reads the IDs.
+	for _, c := range commitments {
+		if c == nil {
+			return nil, xerrors.New("nil commitment")
+		}
+		if group.shares[c.ID] == nil {
+			return nil, xerrors.New("commitment from a participant outside the group")
+		}
+		if c.Hiding == nil || c.Binding == nil || c.Hiding.IsInfinity() || c.Binding.IsInfinity() {
+			return nil, &InvalidContributionError{Signer: int(c.ID), Err: xerrors.New("malformed commitment")}
+		}
+	}
+	sorted := make([]*FrostCommitment, len(commitments))
+	copy(sorted, commitments)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	var encoded []byte
+	for i, c := range sorted {
+		if i > 0 && sorted[i-1].ID == c.ID {
+			return nil, xerrors.Errorf("two commitments from participant %d", c.ID)
+		}
+		encoded = appendUint32BE(encoded, c.ID)
+		encoded = append(encoded, c.Hiding.Sec(true)...)
+		encoded = append(encoded, c.Binding.Sec(true)...)
+	}
+	msgHash := taggedHash(tagFrostMsg, msg)
+	comHash := taggedHash(tagFrostCom, encoded)
+	prefix := append(group.publicKey.Sec(true), msgHash[:]...)
+	prefix = append(prefix, comHash[:]...)
+
+	v := &frostSigningValues{group: group, commitments: sorted, rho: make(map[uint32]Scalar, len(sorted))}
+	// R = sum of D_i + rho_i*E_i
+	scalars := make([]Scalar, 0, 2*len(sorted))
+	points := make([]*s256Point, 0, 2*len(sorted))
+	for _, c := range sorted {
+		h := taggedHash(tagFrostRho, prefix, appendUint32BE(nil, c.ID))
+		var rho Scalar
+		rho.SetBytes(&h)
+		v.rho[c.ID] = rho
+		scalars = append(scalars, *NewScalar(1), rho)
+		points = append(points, c.Hiding, c.Binding)
+	}
+	v.r = MultiScalarMul(scalars, points)
+	if v.r.IsInfinity() {
+		return nil, xerrors.New("group commitment is infinity")
+	}
+	v.c = schnorrChallenge(v.r.XOnly(), group.publicKey.XOnly(), msg)
+	return v, nil
+}
+
+// lagrange returns the Lagrange coefficient of id at 0 over the signing
+// set: the product of x_j / (x_j - x_i) over the other signers j.
+func (v *frostSigningValues) lagrange(id uint32) Scalar {
+	num, den := *NewScalar(1), *NewScalar(1)
+	xi := NewScalar(uint64(id))
+	for _, c := range v.commitments {
+		if c.ID == id {
+			continue
+		}
+		xj := NewScalar(uint64(c.ID))
+		var d Scalar
+		num.Mul(&num, xj)
+		den.Mul(&den, d.Sub(xj, xi))
+	}
+	var l Scalar
+	return *l.Mul(&num, den.inverseVar(&den))
+}
+
+// Sign returns the 32-byte signature share z_i = k_i + lambda_i*c*s_i of
+// msg for the signing set whose commitments, this session's included, are
+// given. k_i = d_i + rho_i*e_i, and k_i and s_i are negated when R or the
+// group key has an odd y, so the result is a BIP340 signature. The nonces
+// are erased first, so every later call fails with ErrNonceUsed.
+func (s *FrostSession) Sign(msg []byte, commitments []*FrostCommitment) ([]byte, error) {
+	s.mu.Lock()
+	nonces := s.nonces
+	s.nonces = nil
+	s.mu.Unlock()
+	if nonces == nil {
+		return nil, ErrNonceUsed
+	}
+	defer func() { *nonces = frostNonces{} }()
+
+	// newFrostSigningValues rejects nil commitments and points, so the
+	// comparison below cannot panic.
+	v, err := newFrostSigningValues(s.key.group, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, c := range v.commitments {
+		if c.ID == s.key.id {
+			if !c.Hiding.Eq(s.commitment.Hiding) || !c.Binding.Eq(s.commitment.Binding) {
+				return nil, xerrors.New("commitment of this session was altered")
+			}
+			found = true
+		}
+	}
+	if !found {
+		return nil, xerrors.New("commitment of this session is missing")
+	}
+	rho := v.rho[s.key.id]
+	var k Scalar
+	k.Mul(&rho, &nonces.binding).Add(&k, &nonces.hiding)
+	if v.r.y.IsOdd() {
+		k.Negate(&k)
+	}
+	secret := s.key.secret
+	if s.key.group.publicKey.y.IsOdd() {
+		secret.Negate(&secret)
+	}
+	lambda := v.lagrange(s.key.id)
+	var z Scalar
+	z.Mul(&lambda, &v.c).Mul(&z, &secret).Add(&z, &k)
+	out := z.Bytes()
+	if ok, err := v.verifyShare(s.key.id, out[:]); err != nil || !ok {
+		return nil, xerrors.New("created an invalid signature share")
+	}
+	return out[:], nil
+}
+
+// verifyShare checks z_i*G = R_i + c*lambda_i*Y_i, with R_i = D_i + rho_i*E_i
+// and the same negations as Sign.
+func (v *frostSigningValues) verifyShare(id uint32, share []byte) (bool, error) {
+	var commitment *FrostCommitment
+	for _, c := range v.commitments {
+		if c.ID == id {
+			commitment = c
+		}
+	}
+	if commitment == nil {
+		return false, xerrors.Errorf("participant %d is not in the signing set", id)
+	}
+	var z Scalar
+	if len(share) != 32 || z.SetByteSlice(share) {
+		return false, nil
+	}
+	rho := v.rho[id]
+	ri := commitment.Hiding.Add(commitment.Binding.ScalarMul(&rho))
+	if v.r.y.IsOdd() {
+		ri = ri.Negate()
+	}
+	yi := v.group.shares[id]
+	if v.group.publicKey.y.IsOdd() {
+		yi = yi.Negate()
+	}
+	// z*G - c*lambda*Y_i must be R_i.
+	t := v.lagrange(id)
+	t.Mul(&t, &v.c).Negate(&t)
+	return DoubleScalarMul(z.Big(), t.Big(), yi).Eq(ri), nil
+}
+
+// FrostVerifyShare reports whether share is the valid signature share of
+// participant id for msg and the given commitments. The aggregator uses it
+// to find a participant that broke the final signature.
+func FrostVerifyShare(group *FrostGroup, id uint32, share, msg []byte, commitments []*FrostCommitment) (bool, error) {
+	v, err := newFrostSigningValues(group, msg, commitments)
+	if err != nil {
+		return false, err
+	}
+	return v.verifyShare(id, share)
+}
+
+// FrostAggregate checks the signature shares, keyed by participant, and
+// sums them into a BIP340 signature of msg that verifies against the x-only
+// group key. An invalid share is reported as an InvalidContributionError
+// with the participant identifier.
+func FrostAggregate(group *FrostGroup, msg []byte, commitments []*FrostCommitment, shares map[uint32][]byte) (*SchnorrSignature, error) {
+	v, err := newFrostSigningValues(group, msg, commitments)
+	if err != nil {
+		return nil, err
+	}
+	sig := &SchnorrSignature{r: *v.r.x}
+	for _, c := range v.commitments {
+		share, ok := shares[c.ID]
+		if !ok {
+			return nil, xerrors.Errorf("missing signature share of participant %d", c.ID)
+		}
+		if ok, err := v.verifyShare(c.ID, share); err != nil || !ok {
+			return nil, &InvalidContributionError{Signer: int(c.ID), Err: xerrors.New("invalid signature share")}
+		}
+		var z Scalar
+		z.SetByteSlice(share)
+		sig.s.Add(&sig.s, &z)
+	}
+	return sig, nil
+}
@@ -0,0 +1,335 @@
+package ecc
+
+import (
+	cryptorand "crypto/rand"
+	"io"
+	"sort"
+
+	"golang.org/x/xerrors"
+)
+
+// FROST tags. There is no BIP for FROST yet, so the hashes are domain
+// separated with tags of our own.
+const (
+	tagFrostDKG   = "FROST/secp256k1/dkg"
+	tagFrostNonce = "FROST/secp256k1/nonce"
+	tagFrostRho   = "FROST/secp256k1/rho"
+	tagFrostMsg   = "FROST/secp256k1/msg"
+	tagFrostCom   = "FROST/secp256k1/com"
+)
+
+// FrostGroup is the public part of a t-of-n FROST key: the group public key
+// Y and the verification share Y_i = s_i*G of every participant, which the
+// aggregator checks signature shares against.
+// https://eprint.iacr.org/2020/852
+type FrostGroup struct {
+	threshold int
+	publicKey *s256Point
+	shares    map[uint32]*s256Point
+}
+
+// Threshold returns the number of participants needed to sign.
+func (g *FrostGroup) Threshold() int {
+	return g.threshold
+}
+
+// PublicKey returns the group public key Y. Its XOnly encoding is the key
+// that FROST signatures verify against with BIP340.
+func (g *FrostGroup) PublicKey() *s256Point {
+	return g.publicKey
+}
+
+// VerificationShare returns Y_i of participant id, or nil if there is no
+// such participant.
+func (g *FrostGroup) VerificationShare(id uint32) *s256Point {
+	return g.shares[id]
+}
+
+// IDs returns the identifiers of all participants in increasing order.
+func (g *FrostGroup) IDs() []uint32 {
+	ids := make([]uint32, 0, len(g.shares))
+	for id := range g.shares {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// FrostKeyShare is the secret share s_i = f(i) of one participant, where f
+// is a polynomial of degree t-1 with f(0) the group secret.
+type FrostKeyShare struct {
+	id     uint32
+	secret Scalar
+	group  *FrostGroup
+}
+
+// ID returns the identifier i of the participant, which is never 0.
+func (k *FrostKeyShare) ID() uint32 {
+	return k.id
+}
+
+// Group returns the public part of the key.
+func (k *FrostKeyShare) Group() *FrostGroup {
+	return k.group
+}
+
+// randomScalar returns a uniformly random non-zero scalar read from rand,
+// which defaults to crypto/rand.
+func randomScalar(rand io.Reader) (Scalar, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	var b [32]byte
+	for {
+		if _, err := io.ReadFull(rand, b[:]); err != nil {
+			return Scalar{}, xerrors.Errorf("reading randomness: %w", err)
+		}
+		var s Scalar
+		if !s.SetBytes(&b) && !s.IsZero() {
+			return s, nil
+		}
+	}
+}
+
+// frostPolynomial is a polynomial with coefficients a_0 ... a_{t-1}.
+type frostPolynomial []Scalar
+
+func newFrostPolynomial(secret *Scalar, threshold int, rand io.Reader) (frostPolynomial, error) {
+	f := make(frostPolynomial, threshold)
+	f[0] = *secret
+	for i := 1; i < threshold; i++ {
+		var err error
+		if f[i], err = randomScalar(rand); err != nil {
+			return nil, err
+		}
+	}
+	return f, nil
+}
+
+// eval returns f(x) with Horner's rule.
+func (f frostPolynomial) eval(x uint32) Scalar {
+	xs := NewScalar(uint64(x))
+	var y Scalar
+	for i := len(f) - 1; i >= 0; i-- {
+		y.Mul(&y, xs).Add(&y, &f[i])
+	}
+	return y
+}
+
+// commitments returns a_j*G for every coefficient, the public commitment
+// to f of Feldman's verifiable secret sharing.
+func (f frostPolynomial) commitments() []*s256Point {
+	c := make([]*s256Point, len(f))
+	for i := range f {
+		c[i] = ScalarBaseMul(&f[i])
+	}
+	return c
+}
+
+// evalCommitments returns f(x)*G from the commitments to f.
+func evalCommitments(c []*s256Point, x uint32) *s256Point {
+	scalars := make([]Scalar, len(c))
+	xs := NewScalar(uint64(x))
+	scalars[0] = *NewScalar(1)
+	for i := 1; i < len(c); i++ {
+		scalars[i].Mul(&scalars[i-1], xs)
+	}
+	return MultiScalarMul(scalars, c)
+}
+
+func checkFrostParams(threshold, n int) error {
+	if threshold < 1 || threshold > n {
+		return xerrors.Errorf("threshold %d is not in [1, %d]", threshold, n)
+	}
+	if uint64(n) > 1<<32-1 {
+		return xerrors.Errorf("%d participants are too many", n)
+	}
+	return nil
+}
+
+// FrostTrustedDealerKeyGen splits secret into n shares of which any
+// threshold can sign, with participant identifiers 1 ... n. A nil secret
+// picks a random one. The dealer learns the group secret and must be
+// trusted to forget it; FrostDKG avoids that.
+func FrostTrustedDealerKeyGen(secret *PrivateKey, threshold, n int, rand io.Reader) ([]*FrostKeyShare, error) {
+	if err := checkFrostParams(threshold, n); err != nil {
+		return nil, err
+	}
+	var s Scalar
+	if secret != nil {
+		s = secret.secret
+	} else {
+		var err error
+		if s, err = randomScalar(rand); err != nil {
+			return nil, err
+		}
+	}
+	f, err := newFrostPolynomial(&s, threshold, rand)
+	if err != nil {
+		return nil, err
+	}
+	group := &FrostGroup{
+		threshold: threshold,
+		publicKey: ScalarBaseMul(&s),
+		shares:    make(map[uint32]*s256Point, n),
+	}
+	keys := make([]*FrostKeyShare, n)
+	for i := range keys {
+		id := uint32(i + 1)
+		keys[i] = &FrostKeyShare{id: id, secret: f.eval(id), group: group}
+		group.shares[id] = ScalarBaseMul(&keys[i].secret)
+	}
+	return keys, nil
+}
+
+// FrostDKG is one participant of the Pedersen distributed key generation
+// of FROST, in which nobody ever learns the group secret:
+//
+//  1. every participant broadcasts Round1, the commitments to a random
+//     polynomial f_i with a proof of knowledge of f_i(0);
+//  2. every participant sends Share(j) = f_i(j) privately to each j;
+//  3. every participant calls Finish with everything it received.
+//
+// The group secret is the sum of all f_i(0) and each s_j the sum of f_i(j).
+type FrostDKG struct {
+	id        uint32
+	threshold int
+	n         int
+	context   []byte
+	f         frostPolynomial
+	round1    *FrostDKGRound1
+}
+
+// FrostDKGRound1 is the broadcast message of a DKG participant.
+type FrostDKGRound1 struct {
+	ID uint32
+	// Commitments are a_ij*G for the coefficients of f_i.
+	Commitments []*s256Point
+	// ProofR and ProofZ are a Schnorr proof of knowledge of a_i0, which
+	// stops a participant from choosing its commitment to cancel the
+	// others out.
+	ProofR *s256Point
+	ProofZ Scalar
+}
+
+// NewFrostDKG starts the DKG for participant id of n, with identifiers
+// 1 ... n. context should be unique to this key generation, such as a
+// session identifier agreed beforehand, so that proofs cannot be replayed.
+func NewFrostDKG(id uint32, threshold, n int, context []byte, rand io.Reader) (*FrostDKG, error) {
+	if err := checkFrostParams(threshold, n); err != nil {
+		return nil, err
+	}
+	if id == 0 || uint64(id) > uint64(n) {
+		return nil, xerrors.Errorf("participant %d is not in [1, %d]", id, n)
+	}
+	secret, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	f, err := newFrostPolynomial(&secret, threshold, rand)
+	if err != nil {
+		return nil, err
+	}
+	d := &FrostDKG{id: id, threshold: threshold, n: n, context: append([]byte(nil), context...), f: f}
+	k, err := randomScalar(rand)
+	if err != nil {
+		return nil, err
+	}
+	d.round1 = &FrostDKGRound1{ID: id, Commitments: f.commitments(), ProofR: ScalarBaseMul(&k)}
+	// z = k + a_i0*c
+	c := d.proofChallenge(id, d.round1.Commitments[0], d.round1.ProofR)
+	d.round1.ProofZ.Mul(&f[0], &c).Add(&d.round1.ProofZ, &k)
+	return d, nil
+}
+
+// proofChallenge returns c = H(id || context || a_i0*G || R).
+func (d *FrostDKG) proofChallenge(id uint32, commitment, r *s256Point) Scalar {
+	h := taggedHash(tagFrostDKG, appendUint32BE(nil, id), d.context, commitment.Sec(true), r.Sec(true))
+	var c Scalar
+	c.SetBytes(&h)
+	return c
+}
+
+// Round1 returns the message to broadcast to all participants.
+func (d *FrostDKG) Round1() *FrostDKGRound1 {
+	return d.round1
+}
+
+// Share returns f_i(to), the secret share to send privately to participant
+// to.
+func (d *FrostDKG) Share(to uint32) (*Scalar, error) {
+	if to == 0 || uint64(to) > uint64(d.n) {
+		return nil, xerrors.Errorf("participant %d is not in [1, %d]", to, d.n)
+	}
+	s := d.f.eval(to)
+	return &s, nil
+}
+
+// Finish checks the broadcasts of all n participants, including this one,
+// and the shares sent to this participant keyed by sender, and returns the
+// key share. A participant whose proof or share is invalid is reported as an
+// InvalidContributionError with its identifier.
+func (d *FrostDKG) Finish(round1 []*FrostDKGRound1, shares map[uint32]*Scalar) (*FrostKeyShare, error) {
+	if len(round1) != d.n {
+		return nil, xerrors.Errorf("got %d broadcasts, want %d", len(round1), d.n)
+	}
+	seen := make(map[uint32]bool, d.n)
+	for _, r := range round1 {
+		if r == nil || r.ID == 0 || uint64(r.ID) > uint64(d.n) || seen[r.ID] {
+			return nil, xerrors.New("broadcasts must come from distinct participants in [1, n]")
+		}
+		seen[r.ID] = true
+		if len(r.Commitments) != d.threshold || r.ProofR == nil {
+			return nil, &InvalidContributionError{Signer: int(r.ID), Err: xerrors.New("malformed commitments")}
+		}
+		for _, c := range r.Commitments {
+			if c == nil || c.IsInfinity() {
+				return nil, &InvalidContributionError{Signer: int(r.ID), Err: xerrors.New("malformed commitments")}
+			}
+		}
+		// z*G - c*A_i0 must be R.
+		c := d.proofChallenge(r.ID, r.Commitments[0], r.ProofR)
+		c.Negate(&c)
+		if !DoubleScalarMul(r.ProofZ.Big(), c.Big(), r.Commitments[0]).Eq(r.ProofR) {
+			return nil, &InvalidContributionError{Signer: int(r.ID), Err: xerrors.New("invalid proof of knowledge")}
+		}
+	}
+
+	var secret Scalar
+	for _, r := range round1 {
+		share, ok := shares[r.ID]
+		if !ok || share == nil {
+			return nil, &InvalidContributionError{Signer: int(r.ID), Err: xerrors.New("missing share")}
+		}
+		if !ScalarBaseMul(share).Eq(evalCommitments(r.Commitments, d.id)) {
+			return nil, &InvalidContributionError{Signer: int(r.ID), Err: xerrors.New("share does not match the commitments")}
+		}
+		secret.Add(&secret, share)
+	}
+
+	// the commitments to the sum of all polynomials give the group key and
+	// every verification share.
+	sum := make([]*s256Point, d.threshold)
+	for j := range sum {
+		sum[j] = S256Infinity()
+		for _, r := range round1 {
+			sum[j] = sum[j].Add(r.Commitments[j])
+		}
+	}
+	group := &FrostGroup{
+		threshold: d.threshold,
+		publicKey: sum[0],
+		shares:    make(map[uint32]*s256Point, d.n),
+	}
+	if group.publicKey.IsInfinity() {
+		return nil, xerrors.New("group public key is infinity")
+	}
+	for id := uint32(1); uint64(id) <= uint64(d.n); id++ {
+		group.shares[id] = evalCommitments(sum, id)
+	}
+	key := &FrostKeyShare{id: d.id, secret: secret, group: group}
+	if !ScalarBaseMul(&secret).Eq(group.shares[d.id]) {
+		return nil, xerrors.New("key share does not match the group")
+	}
+	return key, nil
+}
@@ -0,0 +1,339 @@
+package ecc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// frostSign runs both rounds of FROST with the given signers and returns
+// the aggregate signature.
+func frostSign(t *testing.T, r *rand.Rand, signers []*FrostKeyShare, msg []byte) (*SchnorrSignature, error) {
+	t.Helper()
+	group := signers[0].Group()
+	sessions := make([]*FrostSession, len(signers))
+	commitments := make([]*FrostCommitment, len(signers))
+	for i, key := range signers {
+		s, err := key.NewSigningSession(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions[i] = s
+		commitments[i] = s.Commitment()
+	}
+	shares := make(map[uint32][]byte, len(signers))
+	for i, s := range sessions {
+		share, err := s.Sign(msg, commitments)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := FrostVerifyShare(group, signers[i].ID(), share, msg, commitments)
+		if err != nil || !ok {
+			t.Fatalf("FrostVerifyShare(%d) = %v, %v, want true", signers[i].ID(), ok, err)
+		}
+		shares[signers[i].ID()] = share
+	}
+	return FrostAggregate(group, msg, commitments, shares)
+}
+
+func checkFrostSignature(t *testing.T, group *FrostGroup, msg []byte, sig *SchnorrSignature) {
+	t.Helper()
+	pub, err := ParseXOnly(group.PublicKey().XOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := pub.VerifySchnorr(msg, sig); err != nil || !ok {
+		t.Errorf("VerifySchnorr() = %v, %v, want true", ok, err)
+	}
+}
+
+func TestFrostTrustedDealerKeyGen(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	// 3 gives a group key with an even y and n - 3 one with an odd y.
+	for _, secret := range []*big.Int{big.NewInt(3), new(big.Int).Sub(genN(), big.NewInt(3))} {
+		p, err := NewPrivateKey(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys, err := FrostTrustedDealerKeyGen(p, 3, 5, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		group := keys[0].Group()
+		if !group.PublicKey().Eq(p.p) {
+			t.Fatalf("group key = %v, want %v", group.PublicKey(), p.p)
+		}
+		for _, ids := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+			var signers []*FrostKeyShare
+			for _, i := range ids {
+				signers = append(signers, keys[i])
+			}
+			// several messages, so that R has an odd y in some of them.
+			for m := 0; m < 4; m++ {
+				msg := []byte(fmt.Sprintf("%v %d", ids, m))
+				sig, err := frostSign(t, r, signers, msg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				checkFrostSignature(t, group, msg, sig)
+			}
+		}
+		if _, err := frostSign(t, r, keys[:2], []byte("too few")); err == nil {
+			t.Error("two of a 3-of-5 key signed")
+		}
+	}
+	for _, tt := range []struct{ threshold, n int }{{0, 3}, {4, 3}, {1, 0}} {
+		if _, err := FrostTrustedDealerKeyGen(nil, tt.threshold, tt.n, r); err == nil {
+			t.Errorf("FrostTrustedDealerKeyGen(%d, %d) succeeded", tt.threshold, tt.n)
+		}
+	}
+}
+
+// runFrostDKG simulates the DKG of n participants and lets tamper change
+// the messages before they are delivered.
+func runFrostDKG(t *testing.T, r *rand.Rand, threshold, n int, tamper func(round1 []*FrostDKGRound1, shares []map[uint32]*Scalar)) ([]*FrostKeyShare, error) {
+	t.Helper()
+	parts := make([]*FrostDKG, n)
+	round1 := make([]*FrostDKGRound1, n)
+	for i := range parts {
+		d, err := NewFrostDKG(uint32(i+1), threshold, n, []byte("test session"), r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		parts[i] = d
+		round1[i] = d.Round1()
+	}
+	// shares[j] holds what participant j+1 receives, keyed by sender.
+	shares := make([]map[uint32]*Scalar, n)
+	for j := range shares {
+		shares[j] = make(map[uint32]*Scalar, n)
+		for i, d := range parts {
+			s, err := d.Share(uint32(j + 1))
+			if err != nil {
+				t.Fatal(err)
+			}
+			shares[j][uint32(i+1)] = s
+		}
+	}
+	if tamper != nil {
+		tamper(round1, shares)
+	}
+	keys := make([]*FrostKeyShare, n)
+	for j, d := range parts {
+		key, err := d.Finish(round1, shares[j])
+		if err != nil {
+			return nil, err
+		}
+		keys[j] = key
+	}
+	return keys, nil
+}
+
+func TestFrostDKG(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	keys, err := runFrostDKG(t, r, 3, 4, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := keys[0].Group()
+	for _, key := range keys[1:] {
+		if !key.Group().PublicKey().Eq(group.PublicKey()) {
+			t.Fatal("participants disagree on the group key")
+		}
+		for _, id := range group.IDs() {
+			if !key.Group().VerificationShare(id).Eq(group.VerificationShare(id)) {
+				t.Fatalf("participants disagree on the verification share of %d", id)
+			}
+		}
+	}
+	for _, signers := range [][]*FrostKeyShare{keys[:3], keys[1:], {keys[3], keys[0], keys[2]}} {
+		msg := []byte("spend from the vault")
+		sig, err := frostSign(t, r, signers, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkFrostSignature(t, keys[0].Group(), msg, sig)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(round1 []*FrostDKGRound1, shares []map[uint32]*Scalar)
+		blame  int
+	}{
+		{
+			name: "wrong share",
+			tamper: func(_ []*FrostDKGRound1, shares []map[uint32]*Scalar) {
+				shares[0][3] = new(Scalar).Add(shares[0][3], NewScalar(1))
+			},
+			blame: 3,
+		},
+		{
+			name: "invalid proof",
+			tamper: func(round1 []*FrostDKGRound1, _ []map[uint32]*Scalar) {
+				c := *round1[1]
+				c.ProofZ.Add(&c.ProofZ, NewScalar(1))
+				round1[1] = &c
+			},
+			blame: 2,
+		},
+		{
+			name: "rogue commitment",
+			tamper: func(round1 []*FrostDKGRound1, _ []map[uint32]*Scalar) {
+				// a commitment chosen to cancel out participant 1 cannot
+				// come with a proof of knowledge.
+				c := *round1[3]
+				c.Commitments = append([]*s256Point{c.Commitments[0].Add(round1[0].Commitments[0].Negate())}, c.Commitments[1:]...)
+				round1[3] = &c
+			},
+			blame: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runFrostDKG(t, r, 3, 4, tt.tamper)
+			var contrib *InvalidContributionError
+			if !errors.As(err, &contrib) || contrib.Signer != tt.blame {
+				t.Errorf("Finish() error = %v, want participant %d blamed", err, tt.blame)
+			}
+		})
+	}
+}
+
+func TestFrostSession(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	keys, err := FrostTrustedDealerKeyGen(nil, 2, 3, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := keys[0].Group()
+	s1, _ := keys[0].NewSigningSession(r)
+	s2, _ := keys[1].NewSigningSession(r)
+	commitments := []*FrostCommitment{s1.Commitment(), s2.Commitment()}
+	msg := []byte("once")
+	share1, err := s1.Sign(msg, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s1.Sign([]byte("twice"), commitments); !errors.Is(err, ErrNonceUsed) {
+		t.Errorf("second Sign() error = %v, want %v", err, ErrNonceUsed)
+	}
+
+	// a share that is merely wrong is caught by the aggregator.
+	share2, err := s2.Sign(msg, commitments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var z Scalar
+	z.SetByteSlice(share2)
+	bad := z.Add(&z, NewScalar(1)).Bytes()
+	var contrib *InvalidContributionError
+	_, err = FrostAggregate(group, msg, commitments, map[uint32][]byte{1: share1, 2: bad[:]})
+	if !errors.As(err, &contrib) || contrib.Signer != 2 {
+		t.Errorf("FrostAggregate() error = %v, want participant 2 blamed", err)
+	}
+	sig, err := FrostAggregate(group, msg, commitments, map[uint32][]byte{1: share1, 2: share2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkFrostSignature(t, group, msg, sig)
+
+	s3, _ := keys[2].NewSigningSession(r)
+	if _, err := s3.Sign(msg, commitments); err == nil {
+		t.Error("Sign() succeeded without the session's own commitment")
+	}
+	s3, _ = keys[2].NewSigningSession(r)
+	dup := []*FrostCommitment{s3.Commitment(), s3.Commitment()}
+	if _, err := s3.Sign(msg, dup); err == nil {
+		t.Error("Sign() accepted two commitments from one participant")
+	}
+}
+
+func TestFrostSession_malformedCommitments(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	keys, err := FrostTrustedDealerKeyGen(nil, 2, 3, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := keys[0].Group()
+	msg := []byte("malformed")
+	tests := []struct {
+		name   string
+		mutate func(own, other *FrostCommitment) []*FrostCommitment
+		blame  int
+	}{
+		{
+			name: "nil entry",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				return []*FrostCommitment{own, nil, other}
+			},
+		},
+		{
+			name: "nil entry first",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				return []*FrostCommitment{nil, own, other}
+			},
+		},
+		{
+			name: "nil hiding",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				other.Hiding = nil
+				return []*FrostCommitment{own, other}
+			},
+			blame: 2,
+		},
+		{
+			name: "nil binding",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				other.Binding = nil
+				return []*FrostCommitment{own, other}
+			},
+			blame: 2,
+		},
+		{
+			name: "own hiding nil",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				own.Hiding = nil
+				return []*FrostCommitment{own, other}
+			},
+			blame: 1,
+		},
+		{
+			name: "infinity",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				other.Binding = &s256Point{}
+				return []*FrostCommitment{own, other}
+			},
+			blame: 2,
+		},
+		{
+			name: "outside the group",
+			mutate: func(own, other *FrostCommitment) []*FrostCommitment {
+				other.ID = 7
+				return []*FrostCommitment{own, other}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s1, _ := keys[0].NewSigningSession(r)
+			s2, _ := keys[1].NewSigningSession(r)
+			commitments := tt.mutate(s1.Commitment(), s2.Commitment())
+			_, err := s1.Sign(msg, commitments)
+			if err == nil {
+				t.Fatal("Sign() accepted malformed commitments")
+			}
+			var contrib *InvalidContributionError
+			if tt.blame != 0 && (!errors.As(err, &contrib) || contrib.Signer != tt.blame) {
+				t.Errorf("Sign() error = %v, want participant %d blamed", err, tt.blame)
+			}
+			if _, err := FrostVerifyShare(group, 1, make([]byte, 32), msg, commitments); err == nil {
+				t.Error("FrostVerifyShare() accepted malformed commitments")
+			}
+			if _, err := FrostAggregate(group, msg, commitments, nil); err == nil {
+				t.Error("FrostAggregate() accepted malformed commitments")
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package ecc
+
+import (
+	"math/big"
+)
+
+// secp256k1 has an efficiently computable endomorphism
+// lambda * (x, y) = (beta * x, y), where lambda is a cube root of unity
+// modulo n and beta is a cube root of unity modulo p. Splitting a scalar k
+// into k1 + k2*lambda with k1 and k2 of about 128 bits halves the number of
+// doublings a multiplication needs (Gallant-Lambert-Vanstone).
+var (
+	glvLambda = mustBigFromHex("5363ad4cc05c30e0a5261c028812645a122e22ea20816678df02967c1b23bd72")
+	glvBeta   = mustS256FieldFromHex("7ae96a2b657c07106e64479eac3434e99cf0497512f58995c1396c28719501ee")
+
+	// the short basis (a1, b1), (a2, b2) of the lattice of (x, y) with
+	// x + y*lambda = 0 mod n.
+	glvA1    = mustBigFromHex("3086d221a7d46bcde86c90e49284eb15")
+	glvB1    = new(big.Int).Neg(mustBigFromHex("e4437ed6010e88286f547fa90abfe4c3"))
+	glvA2    = mustBigFromHex("114ca50f7a8e2f3f657c1108d9d44cfd8")
+	glvB2    = mustBigFromHex("3086d221a7d46bcde86c90e49284eb15")
+	glvNegB1 = new(big.Int).Neg(glvB1)
+)
+
+func mustBigFromHex(hex string) *big.Int {
+	n, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		panic("invalid hex constant " + hex)
+	}
+	return n
+}
+
+func mustS256FieldFromHex(hex string) *s256FieldElement {
+	f := new(s256FieldElement)
+	if f.SetBig(mustBigFromHex(hex)) {
+		panic("field constant out of range " + hex)
+	}
+	return f
+}
+
+// roundDiv returns round(a / b) for non-negative a and positive b.
+func roundDiv(a, b *big.Int) *big.Int {
+	q := new(big.Int).Rsh(b, 1)
+	q.Add(q, a)
+	return q.Quo(q, b)
+}
+
+// splitScalar returns k1 and k2, both of about 128 bits and possibly
+// negative, with k = k1 + k2*lambda mod n. k must be reduced modulo n.
+func splitScalar(k *big.Int) (k1, k2 *big.Int) {
+	n := genN()
+	// c1 = round(b2*k/n), c2 = round(-b1*k/n)
+	c1 := roundDiv(new(big.Int).Mul(glvB2, k), n)
+	c2 := roundDiv(new(big.Int).Mul(glvNegB1, k), n)
+	// k1 = k - c1*a1 - c2*a2
+	k1 = new(big.Int).Sub(k, new(big.Int).Mul(c1, glvA1))
+	k1.Sub(k1, new(big.Int).Mul(c2, glvA2))
+	// k2 = -c1*b1 - c2*b2
+	k2 = new(big.Int).Mul(c1, glvNegB1)
+	k2.Sub(k2, new(big.Int).Mul(c2, glvB2))
+	return k1, k2
+}
+
+// ScalarMul returns k * s. It runs in variable time.
+func (s *s256Point) ScalarMul(k *Scalar) *s256Point {
+	return s.glvMul(k)
+}
+
+// glvMul returns k * s. Both halves of the split
+// scalar are walked in a single interleaved wNAF loop, so they share one
+// chain of about 128 doublings.
+func (s *s256Point) glvMul(k *Scalar) *s256Point {
+	if s.IsInfinity() || k.IsZero() {
+		return S256Infinity()
+	}
+	k1, k2 := splitScalar(k.Big())
+	table1 := oddMultiples(s, wnafWindowP)
+	// lambda * (i*P) = (beta * x, y) for every odd multiple i*P.
+	table2 := make([]s256Point, len(table1))
+	for i := range table1 {
+		table2[i] = s256Point{new(s256FieldElement).Mul(table1[i].x, glvBeta), table1[i].y, nil}
+	}
+	// a negative half is handled by negating its table.
+	if k1.Sign() < 0 {
+		k1.Neg(k1)
+		negateTable(table1)
+	}
+	if k2.Sign() < 0 {
+		k2.Neg(k2)
+		negateTable(table2)
+	}
+	naf1 := wnaf(k1, wnafWindowP)
+	naf2 := wnaf(k2, wnafWindowP)
+	length := len(naf1)
+	if len(naf2) > length {
+		length = len(naf2)
+	}
+	var acc s256JacobianPoint
+	acc.setInfinity()
+	for i := length - 1; i >= 0; i-- {
+		acc.double(&acc)
+		if i < len(naf1) {
+			addWnafDigit(&acc, table1, naf1[i])
+		}
+		if i < len(naf2) {
+			addWnafDigit(&acc, table2, naf2[i])
+		}
+	}
+	return newS256PointFromJacobian(&acc)
+}
+
+func negateTable(table []s256Point) {
+	for i := range table {
+		table[i].y = new(s256FieldElement).Neg(table[i].y)
+	}
+}
@@ -0,0 +1,90 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func Test_glvConstants(t *testing.T) {
+	n := genN()
+	// lambda**3 = 1 mod n
+	if got := new(big.Int).Exp(glvLambda, big.NewInt(3), n); got.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("lambda**3 = %x, want 1", got)
+	}
+	// beta**3 = 1 mod p
+	got := new(s256FieldElement).Square(glvBeta)
+	got.Mul(got, glvBeta)
+	if !got.Equal(newS256FieldElementFromUint64(1)) {
+		t.Errorf("beta**3 = %v, want 1", got)
+	}
+	// lambda * G = (beta * Gx, Gy)
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &s256Point{new(s256FieldElement).Mul(g.x, glvBeta), g.y, nil}
+	if lg := g.FastRMul(glvLambda); !lg.Eq(want) {
+		t.Errorf("lambda*G = x:%v y:%v want x:%v y:%v", lg.x, lg.y, want.x, want.y)
+	}
+}
+
+func Test_splitScalar(t *testing.T) {
+	n := genN()
+	bound := new(big.Int).Lsh(big.NewInt(1), 129)
+	r := rand.New(rand.NewSource(1))
+	scalars := []*big.Int{big.NewInt(0), big.NewInt(1), new(big.Int).Sub(n, big.NewInt(1)), new(big.Int).Set(glvLambda)}
+	for i := 0; i < 200; i++ {
+		scalars = append(scalars, new(big.Int).Rand(r, n))
+	}
+	for _, k := range scalars {
+		k1, k2 := splitScalar(k)
+		got := new(big.Int).Mul(k2, glvLambda)
+		got.Add(got, k1).Mod(got, n)
+		if got.Cmp(k) != 0 {
+			t.Errorf("splitScalar(%x): k1 + k2*lambda = %x", k, got)
+		}
+		if new(big.Int).Abs(k1).Cmp(bound) >= 0 || new(big.Int).Abs(k2).Cmp(bound) >= 0 {
+			t.Errorf("splitScalar(%x) = %x, %x is too large", k, k1, k2)
+		}
+	}
+}
+
+func Test_s256Point_SRMul_matchesFastRMul(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := genN()
+	r := rand.New(rand.NewSource(2))
+	for i := 0; i < 30; i++ {
+		p := baseMul(new(big.Int).Rand(r, n))
+		k := new(big.Int).Rand(r, n)
+		if got, want := p.SRMul(k), p.FastRMul(k); !got.Eq(want) {
+			t.Errorf("SRMul(%x) = x:%v y:%v want x:%v y:%v", k, got.x, got.y, want.x, want.y)
+		}
+	}
+	if got := g.SRMul(n); !got.IsInfinity() {
+		t.Errorf("n*G = x:%v y:%v want infinity", got.x, got.y)
+	}
+	if got := S256Infinity().SRMul(big.NewInt(5)); !got.IsInfinity() {
+		t.Errorf("5*infinity = x:%v y:%v want infinity", got.x, got.y)
+	}
+}
+
+func FuzzSRMul(f *testing.F) {
+	f.Add([]byte{0x07}, []byte{0x01})
+	f.Add([]byte{0xff, 0xff}, []byte{0x05, 0xcd})
+	f.Add(mustDecodeString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"), mustDecodeString("5363ad4cc05c30e0a5261c028812645a122e22ea20816678df02967c1b23bd72"))
+	f.Fuzz(func(t *testing.T, pointSecret, coefficient []byte) {
+		if len(pointSecret) > 32 || len(coefficient) > 64 {
+			t.Skip()
+		}
+		p := baseMul(new(big.Int).SetBytes(pointSecret))
+		k := new(big.Int).SetBytes(coefficient)
+		want := p.FastRMul(new(big.Int).Mod(k, genN()))
+		if got := p.SRMul(k); !got.Eq(want) {
+			t.Errorf("SRMul(%x) = x:%v y:%v want x:%v y:%v", k, got.x, got.y, want.x, want.y)
+		}
+	})
+}
@@ -0,0 +1,174 @@
+package ecc
+
+import (
+	"math/big"
+)
+
+// jacobianPoint is a point in Jacobian coordinates, where the affine point is
+// (x/z**2, y/z**3). Addition and doubling need no modular inversion in this
+// form, so chains of operations only pay for one inversion when converting
+// back to affine. z = 0 means infinity.
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+// jacobianCurve holds the parameters the Jacobian formulas need.
+type jacobianCurve struct {
+	prime *big.Int
+	a     *big.Int
+}
+
+func newJacobianCurve(p *point) *jacobianCurve {
+	return &jacobianCurve{prime: p.a.prime, a: p.a.number}
+}
+
+func (c *jacobianCurve) mod(x *big.Int) *big.Int {
+	return x.Mod(x, c.prime)
+}
+
+func (c *jacobianCurve) infinity() *jacobianPoint {
+	return &jacobianPoint{big.NewInt(1), big.NewInt(1), big.NewInt(0)}
+}
+
+// fromAffine lifts an affine point to Jacobian coordinates with z = 1.
+func (c *jacobianCurve) fromAffine(p *point) *jacobianPoint {
+	if p.x == nil {
+		return c.infinity()
+	}
+	return &jacobianPoint{
+		c.mod(new(big.Int).Set(p.x.number)),
+		c.mod(new(big.Int).Set(p.y.number)),
+		big.NewInt(1),
+	}
+}
+
+// toAffine converts j back to affine coordinates. It returns nil coordinates
+// for infinity.
+func (c *jacobianCurve) toAffine(j *jacobianPoint) (x, y *fieldElement) {
+	if j.z.Sign() == 0 {
+		return nil, nil
+	}
+	zInv := new(big.Int).ModInverse(j.z, c.prime)
+	zInv2 := c.mod(new(big.Int).Mul(zInv, zInv))
+	zInv3 := c.mod(new(big.Int).Mul(zInv2, zInv))
+	return &fieldElement{c.mod(new(big.Int).Mul(j.x, zInv2)), c.prime},
+		&fieldElement{c.mod(new(big.Int).Mul(j.y, zInv3)), c.prime}
+}
+
+// double returns 2*p using dbl-2007-bl.
+func (c *jacobianCurve) double(p *jacobianPoint) *jacobianPoint {
+	if p.z.Sign() == 0 || p.y.Sign() == 0 {
+		return c.infinity()
+	}
+	xx := c.mod(new(big.Int).Mul(p.x, p.x))
+	yy := c.mod(new(big.Int).Mul(p.y, p.y))
+	yyyy := c.mod(new(big.Int).Mul(yy, yy))
+	zz := c.mod(new(big.Int).Mul(p.z, p.z))
+	// s = 2*((x1+yy)**2-xx-yyyy)
+	s := new(big.Int).Add(p.x, yy)
+	s.Mul(s, s).Sub(s, xx).Sub(s, yyyy).Lsh(s, 1)
+	c.mod(s)
+	// m = 3*xx+a*zz**2
+	m := new(big.Int).Mul(zz, zz)
+	m.Mul(m, c.a).Add(m, new(big.Int).Mul(xx, big.NewInt(3)))
+	c.mod(m)
+	// x3 = m**2-2*s
+	x3 := new(big.Int).Mul(m, m)
+	x3.Sub(x3, new(big.Int).Lsh(s, 1))
+	c.mod(x3)
+	// y3 = m*(s-x3)-8*yyyy
+	y3 := new(big.Int).Sub(s, x3)
+	y3.Mul(y3, m).Sub(y3, new(big.Int).Lsh(yyyy, 3))
+	c.mod(y3)
+	// z3 = (y1+z1)**2-yy-zz
+	z3 := new(big.Int).Add(p.y, p.z)
+	z3.Mul(z3, z3).Sub(z3, yy).Sub(z3, zz)
+	c.mod(z3)
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// add returns p+q using add-2007-bl.
+func (c *jacobianCurve) add(p, q *jacobianPoint) *jacobianPoint {
+	if p.z.Sign() == 0 {
+		return q
+	}
+	if q.z.Sign() == 0 {
+		return p
+	}
+	z1z1 := c.mod(new(big.Int).Mul(p.z, p.z))
+	z2z2 := c.mod(new(big.Int).Mul(q.z, q.z))
+	u1 := c.mod(new(big.Int).Mul(p.x, z2z2))
+	u2 := c.mod(new(big.Int).Mul(q.x, z1z1))
+	s1 := new(big.Int).Mul(p.y, q.z)
+	c.mod(s1.Mul(s1, z2z2))
+	s2 := new(big.Int).Mul(q.y, p.z)
+	c.mod(s2.Mul(s2, z1z1))
+	h := c.mod(new(big.Int).Sub(u2, u1))
+	r := c.mod(new(big.Int).Sub(s2, s1))
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.double(p)
+		}
+		return c.infinity()
+	}
+	r.Lsh(r, 1)
+	// i = (2*h)**2, j = h*i, v = u1*i
+	i := new(big.Int).Lsh(h, 1)
+	c.mod(i.Mul(i, i))
+	j := c.mod(new(big.Int).Mul(h, i))
+	v := c.mod(new(big.Int).Mul(u1, i))
+	// x3 = r**2-j-2*v
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j).Sub(x3, new(big.Int).Lsh(v, 1))
+	c.mod(x3)
+	// y3 = r*(v-x3)-2*s1*j
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r).Sub(y3, new(big.Int).Lsh(new(big.Int).Mul(s1, j), 1))
+	c.mod(y3)
+	// z3 = ((z1+z2)**2-z1z1-z2z2)*h
+	z3 := new(big.Int).Add(p.z, q.z)
+	z3.Mul(z3, z3).Sub(z3, z1z1).Sub(z3, z2z2).Mul(z3, h)
+	c.mod(z3)
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// addMixed returns p+q for an affine q (z = 1) using madd-2007-bl.
+func (c *jacobianCurve) addMixed(p *jacobianPoint, q *point) *jacobianPoint {
+	if q.x == nil {
+		return p
+	}
+	if p.z.Sign() == 0 {
+		return c.fromAffine(q)
+	}
+	z1z1 := c.mod(new(big.Int).Mul(p.z, p.z))
+	u2 := c.mod(new(big.Int).Mul(q.x.number, z1z1))
+	s2 := new(big.Int).Mul(q.y.number, p.z)
+	c.mod(s2.Mul(s2, z1z1))
+	h := c.mod(new(big.Int).Sub(u2, p.x))
+	r := c.mod(new(big.Int).Sub(s2, p.y))
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return c.double(p)
+		}
+		return c.infinity()
+	}
+	r.Lsh(r, 1)
+	// hh = h**2, i = 4*hh, j = h*i, v = x1*i
+	hh := c.mod(new(big.Int).Mul(h, h))
+	i := c.mod(new(big.Int).Lsh(hh, 2))
+	j := c.mod(new(big.Int).Mul(h, i))
+	v := c.mod(new(big.Int).Mul(p.x, i))
+	// x3 = r**2-j-2*v
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j).Sub(x3, new(big.Int).Lsh(v, 1))
+	c.mod(x3)
+	// y3 = r*(v-x3)-2*y1*j
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r).Sub(y3, new(big.Int).Lsh(new(big.Int).Mul(p.y, j), 1))
+	c.mod(y3)
+	// z3 = (z1+h)**2-z1z1-hh
+	z3 := new(big.Int).Add(p.z, h)
+	z3.Mul(z3, z3).Sub(z3, z1z1).Sub(z3, hh)
+	c.mod(z3)
+	return &jacobianPoint{x3, y3, z3}
+}
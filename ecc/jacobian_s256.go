@@ -0,0 +1,242 @@
+package ecc
+
+// s256JacobianPoint is the secp256k1 counterpart of jacobianPoint, using the
+// fixed-limb field so that no operation allocates. z = 0 means infinity.
+type s256JacobianPoint struct {
+	x, y, z s256FieldElement
+}
+
+func (j *s256JacobianPoint) isInfinity() bool {
+	return j.z.IsZero()
+}
+
+func (j *s256JacobianPoint) setInfinity() *s256JacobianPoint {
+	*j = s256JacobianPoint{x: s256FieldElement{1}, y: s256FieldElement{1}}
+	return j
+}
+
+// setAffine lifts an affine point to Jacobian coordinates with z = 1.
+func (j *s256JacobianPoint) setAffine(p *s256Point) *s256JacobianPoint {
+	if p.x == nil {
+		return j.setInfinity()
+	}
+	j.x.Set(p.x)
+	j.y.Set(p.y)
+	j.z = s256FieldElement{1}
+	return j
+}
+
+// toAffine converts j back to affine coordinates. It returns nil coordinates
+// for infinity.
+func (j *s256JacobianPoint) toAffine() (x, y *s256FieldElement) {
+	if j.isInfinity() {
+		return nil, nil
+	}
+	var zInv, zInv2, zInv3 s256FieldElement
+	zInv.Inverse(&j.z)
+	zInv2.Square(&zInv)
+	zInv3.Mul(&zInv2, &zInv)
+	x = new(s256FieldElement).Mul(&j.x, &zInv2)
+	y = new(s256FieldElement).Mul(&j.y, &zInv3)
+	return x, y
+}
+
+// selectFrom sets j to a when cond is 1 and to b when cond is 0.
+func (j *s256JacobianPoint) selectFrom(a, b *s256JacobianPoint, cond uint64) *s256JacobianPoint {
+	j.x.selectFrom(&a.x, &b.x, cond)
+	j.y.selectFrom(&a.y, &b.y, cond)
+	j.z.selectFrom(&a.z, &b.z, cond)
+	return j
+}
+
+// double sets j = 2*p using dbl-2009-l, which relies on a = 0. It does not
+// branch: infinity and y = 0 both give z3 = 0, which is then replaced by
+// the canonical infinity.
+func (j *s256JacobianPoint) double(p *s256JacobianPoint) *s256JacobianPoint {
+	var a, b, c, d, e, f, t s256FieldElement
+	a.Square(&p.x)
+	b.Square(&p.y)
+	c.Square(&b)
+	// d = 2*((x1+b)**2-a-c)
+	d.Add(&p.x, &b)
+	d.Square(&d)
+	d.Sub(&d, &a)
+	d.Sub(&d, &c)
+	d.Add(&d, &d)
+	// e = 3*a, f = e**2
+	e.Add(&a, &a)
+	e.Add(&e, &a)
+	f.Square(&e)
+	// z3 = 2*y1*z1, computed first since j may alias p
+	t.Mul(&p.y, &p.z)
+	j.z.Add(&t, &t)
+	// x3 = f-2*d
+	j.x.Sub(&f, &d)
+	j.x.Sub(&j.x, &d)
+	// y3 = e*(d-x3)-8*c
+	t.Sub(&d, &j.x)
+	t.Mul(&e, &t)
+	c.Add(&c, &c)
+	c.Add(&c, &c)
+	c.Add(&c, &c)
+	j.y.Sub(&t, &c)
+	var inf s256JacobianPoint
+	inf.setInfinity()
+	return j.selectFrom(&inf, j, j.z.ctIsZero())
+}
+
+// add sets j = p+q using add-2007-bl.
+func (j *s256JacobianPoint) add(p, q *s256JacobianPoint) *s256JacobianPoint {
+	if p.isInfinity() {
+		*j = *q
+		return j
+	}
+	if q.isInfinity() {
+		*j = *p
+		return j
+	}
+	var z1z1, z2z2, u1, u2, s1, s2, h, r, i, jj, v s256FieldElement
+	z1z1.Square(&p.z)
+	z2z2.Square(&q.z)
+	u1.Mul(&p.x, &z2z2)
+	u2.Mul(&q.x, &z1z1)
+	s1.Mul(&p.y, &q.z)
+	s1.Mul(&s1, &z2z2)
+	s2.Mul(&q.y, &p.z)
+	s2.Mul(&s2, &z1z1)
+	h.Sub(&u2, &u1)
+	r.Sub(&s2, &s1)
+	if h.IsZero() {
+		if r.IsZero() {
+			return j.double(p)
+		}
+		return j.setInfinity()
+	}
+	r.Add(&r, &r)
+	// i = (2*h)**2, j = h*i, v = u1*i
+	i.Add(&h, &h)
+	i.Square(&i)
+	jj.Mul(&h, &i)
+	v.Mul(&u1, &i)
+	// z3 = ((z1+z2)**2-z1z1-z2z2)*h
+	var z3 s256FieldElement
+	z3.Add(&p.z, &q.z)
+	z3.Square(&z3)
+	z3.Sub(&z3, &z1z1)
+	z3.Sub(&z3, &z2z2)
+	z3.Mul(&z3, &h)
+	// x3 = r**2-j-2*v
+	var x3 s256FieldElement
+	x3.Square(&r)
+	x3.Sub(&x3, &jj)
+	x3.Sub(&x3, &v)
+	x3.Sub(&x3, &v)
+	// y3 = r*(v-x3)-2*s1*j
+	var y3 s256FieldElement
+	y3.Sub(&v, &x3)
+	y3.Mul(&y3, &r)
+	s1.Mul(&s1, &jj)
+	s1.Add(&s1, &s1)
+	y3.Sub(&y3, &s1)
+	j.x, j.y, j.z = x3, y3, z3
+	return j
+}
+
+// addMixed sets j = p+q for an affine q (z = 1) using madd-2007-bl.
+func (j *s256JacobianPoint) addMixed(p *s256JacobianPoint, q *s256Point) *s256JacobianPoint {
+	if q.x == nil {
+		*j = *p
+		return j
+	}
+	if p.isInfinity() {
+		return j.setAffine(q)
+	}
+	var z1z1, u2, s2, h, hh, r, i, jj, v s256FieldElement
+	z1z1.Square(&p.z)
+	u2.Mul(q.x, &z1z1)
+	s2.Mul(q.y, &p.z)
+	s2.Mul(&s2, &z1z1)
+	h.Sub(&u2, &p.x)
+	r.Sub(&s2, &p.y)
+	if h.IsZero() {
+		if r.IsZero() {
+			return j.double(p)
+		}
+		return j.setInfinity()
+	}
+	r.Add(&r, &r)
+	// hh = h**2, i = 4*hh, j = h*i, v = x1*i
+	hh.Square(&h)
+	i.Add(&hh, &hh)
+	i.Add(&i, &i)
+	jj.Mul(&h, &i)
+	v.Mul(&p.x, &i)
+	// z3 = (z1+h)**2-z1z1-hh
+	var z3 s256FieldElement
+	z3.Add(&p.z, &h)
+	z3.Square(&z3)
+	z3.Sub(&z3, &z1z1)
+	z3.Sub(&z3, &hh)
+	// x3 = r**2-j-2*v
+	var x3 s256FieldElement
+	x3.Square(&r)
+	x3.Sub(&x3, &jj)
+	x3.Sub(&x3, &v)
+	x3.Sub(&x3, &v)
+	// y3 = r*(v-x3)-2*y1*j
+	var y3 s256FieldElement
+	y3.Sub(&v, &x3)
+	y3.Mul(&y3, &r)
+	var t s256FieldElement
+	t.Mul(&p.y, &jj)
+	t.Add(&t, &t)
+	y3.Sub(&y3, &t)
+	j.x, j.y, j.z = x3, y3, z3
+	return j
+}
+
+// addMixedConst sets j = p+q for an affine q that is not infinity. Unlike
+// addMixed it handles p = infinity, p = q and p = -q without branching, by
+// computing every candidate and picking one with masks.
+func (j *s256JacobianPoint) addMixedConst(p *s256JacobianPoint, q *s256Point) *s256JacobianPoint {
+	var z1z1, u2, s2, h, hh, r, i, jj, v, t s256FieldElement
+	z1z1.Square(&p.z)
+	u2.Mul(q.x, &z1z1)
+	s2.Mul(q.y, &p.z)
+	s2.Mul(&s2, &z1z1)
+	h.Sub(&u2, &p.x)
+	r.Sub(&s2, &p.y)
+	hZero, rZero := h.ctIsZero(), r.ctIsZero()
+	pInf := p.z.ctIsZero()
+	r.Add(&r, &r)
+	hh.Square(&h)
+	i.Add(&hh, &hh)
+	i.Add(&i, &i)
+	jj.Mul(&h, &i)
+	v.Mul(&p.x, &i)
+	var sum s256JacobianPoint
+	sum.z.Add(&p.z, &h)
+	sum.z.Square(&sum.z)
+	sum.z.Sub(&sum.z, &z1z1)
+	sum.z.Sub(&sum.z, &hh)
+	sum.x.Square(&r)
+	sum.x.Sub(&sum.x, &jj)
+	sum.x.Sub(&sum.x, &v)
+	sum.x.Sub(&sum.x, &v)
+	sum.y.Sub(&v, &sum.x)
+	sum.y.Mul(&sum.y, &r)
+	t.Mul(&p.y, &jj)
+	t.Add(&t, &t)
+	sum.y.Sub(&sum.y, &t)
+
+	var dbl, inf, lifted s256JacobianPoint
+	dbl.double(p)
+	inf.setInfinity()
+	lifted.x.Set(q.x)
+	lifted.y.Set(q.y)
+	lifted.z = s256FieldElement{1}
+	// h = 0 means p = q when r = 0 and p = -q otherwise.
+	sum.selectFrom(&dbl, &sum, hZero&rZero)
+	sum.selectFrom(&inf, &sum, hZero&(rZero^1))
+	return j.selectFrom(&lifted, &sum, pInf)
+}
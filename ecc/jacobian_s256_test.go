@@ -0,0 +1,88 @@
+package ecc
+
+import (
+	"math/big"
+	"testing"
+)
+
+// The secp256k1 Jacobian code is checked against the generic big.Int
+// implementation running on the same curve.
+func Test_s256Jacobian_matchesGeneric(t *testing.T) {
+	prime := genPrime()
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range []*big.Int{
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(3),
+		big.NewInt(1485),
+		mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"),
+		mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"),
+	} {
+		generic := &point{
+			x: &fieldElement{g.x.Big(), prime},
+			y: &fieldElement{g.y.Big(), prime},
+			a: &fieldElement{big.NewInt(0), prime},
+			b: &fieldElement{big.NewInt(7), prime},
+		}
+		generic, err := generic.FastRMul(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := g.FastRMul(k)
+		if got.x.Big().Cmp(generic.x.number) != 0 || got.y.Big().Cmp(generic.y.number) != 0 {
+			t.Errorf("%x*G = (%v, %v), want (%x, %x)", k, got.x, got.y, generic.x.number, generic.y.number)
+		}
+	}
+}
+
+func Test_s256Jacobian_infinity(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	neg := &s256Point{g.x, new(s256FieldElement).Neg(g.y), g.n}
+	if sum := g.Add(neg); !sum.IsInfinity() {
+		t.Errorf("G + -G = (%v, %v), want infinity", sum.x, sum.y)
+	}
+	double := g.Add(g)
+	want := g.FastRMul(big.NewInt(2))
+	if !double.Eq(want) {
+		t.Errorf("G + G = (%v, %v), want (%v, %v)", double.x, double.y, want.x, want.y)
+	}
+}
+
+func Test_s256Jacobian_addMixedConst(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	neg := &s256Point{g.x, new(s256FieldElement).Neg(g.y), g.n}
+	three := g.FastRMul(big.NewInt(3))
+	var inf, gj, twoG s256JacobianPoint
+	inf.setInfinity()
+	gj.setAffine(g)
+	twoG.double(&gj)
+	tests := []struct {
+		name string
+		p    *s256JacobianPoint
+		q    *s256Point
+	}{
+		{"infinity + G", &inf, g},
+		{"G + G", &gj, g},
+		{"G + -G", &gj, neg},
+		{"2G + 3G", &twoG, three},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, want s256JacobianPoint
+			got.addMixedConst(tt.p, tt.q)
+			want.addMixed(tt.p, tt.q)
+			if !newS256PointFromJacobian(&got).Eq(newS256PointFromJacobian(&want)) {
+				t.Errorf("addMixedConst() = %v, want %v", newS256PointFromJacobian(&got), newS256PointFromJacobian(&want))
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package ecc
+
+import (
+	"math/big"
+	"testing"
+)
+
+// affineAdd is the textbook chord-and-tangent addition kept as a reference
+// for the Jacobian formulas. nil x means infinity.
+func affineAdd(prime, a, x1, y1, x2, y2 *big.Int) (*big.Int, *big.Int) {
+	if x1 == nil {
+		return x2, y2
+	}
+	if x2 == nil {
+		return x1, y1
+	}
+	var s *big.Int
+	if x1.Cmp(x2) != 0 {
+		num := new(big.Int).Sub(y2, y1)
+		den := new(big.Int).Sub(x2, x1)
+		den.Mod(den, prime).ModInverse(den, prime)
+		s = num.Mul(num, den)
+	} else if y1.Cmp(y2) == 0 && y1.Sign() != 0 {
+		num := new(big.Int).Mul(x1, x1)
+		num.Mul(num, big.NewInt(3)).Add(num, a)
+		den := new(big.Int).Lsh(y1, 1)
+		den.Mod(den, prime).ModInverse(den, prime)
+		s = num.Mul(num, den)
+	} else {
+		return nil, nil
+	}
+	s.Mod(s, prime)
+	x3 := new(big.Int).Mul(s, s)
+	x3.Sub(x3, x1).Sub(x3, x2).Mod(x3, prime)
+	y3 := new(big.Int).Sub(x1, x3)
+	y3.Mul(y3, s).Sub(y3, y1).Mod(y3, prime)
+	return x3, y3
+}
+
+func f223Points(t *testing.T) []*point {
+	prime := big.NewInt(223)
+	var points []*point
+	for x := int64(0); x < 223; x++ {
+		for y := int64(0); y < 223; y++ {
+			p, err := NewPoint(
+				&fieldElement{big.NewInt(x), prime},
+				&fieldElement{big.NewInt(y), prime},
+				&fieldElement{big.NewInt(0), prime},
+				&fieldElement{big.NewInt(7), prime},
+			)
+			if err == nil {
+				points = append(points, p)
+			}
+		}
+	}
+	if len(points) == 0 {
+		t.Fatal("no points found on the curve")
+	}
+	return points
+}
+
+func Test_jacobian_matchesAffine(t *testing.T) {
+	prime := big.NewInt(223)
+	points := f223Points(t)
+	for i, p := range points {
+		// pair each point with a spread of others, itself and its negation.
+		for _, q := range []*point{p, points[(i*31+7)%len(points)], points[(i+1)%len(points)], points[len(points)-1-i]} {
+			wantX, wantY := affineAdd(prime, big.NewInt(0), p.x.number, p.y.number, q.x.number, q.y.number)
+			got, err := p.Add(q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if wantX == nil {
+				if got.x != nil {
+					t.Errorf("(%v,%v)+(%v,%v) = (%v,%v), want infinity", p.x.number, p.y.number, q.x.number, q.y.number, got.x.number, got.y.number)
+				}
+				continue
+			}
+			if got.x == nil || got.x.number.Cmp(wantX) != 0 || got.y.number.Cmp(wantY) != 0 {
+				t.Errorf("(%v,%v)+(%v,%v) = %v, want (%v,%v)", p.x.number, p.y.number, q.x.number, q.y.number, got, wantX, wantY)
+			}
+		}
+	}
+}
+
+func Test_jacobian_FastRMul_matchesAffine(t *testing.T) {
+	prime := big.NewInt(223)
+	p := f223Points(t)[5]
+	var wantX, wantY *big.Int
+	for k := int64(1); k < 60; k++ {
+		wantX, wantY = affineAdd(prime, big.NewInt(0), wantX, wantY, p.x.number, p.y.number)
+		got, err := p.FastRMul(big.NewInt(k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wantX == nil {
+			if got.x != nil {
+				t.Errorf("%d*P = (%v,%v), want infinity", k, got.x.number, got.y.number)
+			}
+			continue
+		}
+		if got.x == nil || got.x.number.Cmp(wantX) != 0 || got.y.number.Cmp(wantY) != 0 {
+			t.Errorf("%d*P = %v, want (%v,%v)", k, got, wantX, wantY)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package ecc
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// messageMagic is the prefix Bitcoin Core puts in front of a signed
+// message. Its first byte is the length of the rest.
+const messageMagic = "\x18Bitcoin Signed Message:\n"
+
+// AddressType selects the address a signed message is bound to. It is
+// recorded in the header byte of the signature as described in BIP137.
+type AddressType int
+
+const (
+	// P2PKHUncompressed is a P2PKH address of the uncompressed key, header
+	// 27-30.
+	P2PKHUncompressed AddressType = iota
+	// P2PKH is a P2PKH address of the compressed key, header 31-34.
+	P2PKH
+	// P2SHP2WPKH is a BIP49 nested segwit address, header 35-38.
+	P2SHP2WPKH
+	// P2WPKH is a native segwit v0 address, header 39-42.
+	P2WPKH
+)
+
+// appendVarint appends n as a Bitcoin CompactSize integer.
+func appendVarint(b []byte, n uint64) []byte {
+	switch {
+	case n < 0xfd:
+		return append(b, byte(n))
+	case n <= 0xffff:
+		var buf [2]byte
+		binary.LittleEndian.PutUint16(buf[:], uint16(n))
+		return append(append(b, 0xfd), buf[:]...)
+	case n <= 0xffffffff:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], uint32(n))
+		return append(append(b, 0xfe), buf[:]...)
+	default:
+		var buf [8]byte
+		binary.LittleEndian.PutUint64(buf[:], n)
+		return append(append(b, 0xff), buf[:]...)
+	}
+}
+
+func appendVarBytes(b, data []byte) []byte {
+	return append(appendVarint(b, uint64(len(data))), data...)
+}
+
+// MessageHash returns the double SHA256 of the magic prefix and message,
+// which is what signmessage signs.
+func MessageHash(message string) []byte {
+	b := []byte(messageMagic)
+	b = appendVarint(b, uint64(len(message)))
+	b = append(b, message...)
+	return Hash256(b)
+}
+
+// SignMessage signs message the way Bitcoin Core's signmessage does and
+// returns the base64 encoded 65-byte signature. The header byte records
+// addrType, so a verifier knows which address the key stands for.
+func (p *PrivateKey) SignMessage(message string, addrType AddressType) (string, error) {
+	if addrType < P2PKHUncompressed || addrType > P2WPKH {
+		return "", xerrors.Errorf("unknown address type %d", addrType)
+	}
+	sig, err := p.SignCompact(MessageHash(message), addrType != P2PKHUncompressed)
+	if err != nil {
+		return "", err
+	}
+	// SignCompact already added 4 for a compressed key.
+	if addrType > P2PKH {
+		sig[0] += byte(addrType-P2PKH) * compactSigCompressed
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// VerifyMessage reports whether signature is a valid signature of message
+// by the key behind address, which may be a mainnet or testnet P2PKH,
+// P2SH-P2WPKH or P2WPKH address. The header byte must name the type of
+// address, as BIP137 requires. VerifyMessageElectrum is more lenient.
+func VerifyMessage(address, signature, message string) (bool, error) {
+	return verifyMessage(address, signature, message, false)
+}
+
+// VerifyMessageElectrum is VerifyMessage, except that like Electrum it
+// accepts a compressed key header (31-42) for any of the three compressed
+// address types, since many wallets sign segwit addresses with header 31-34.
+func VerifyMessageElectrum(address, signature, message string) (bool, error) {
+	return verifyMessage(address, signature, message, true)
+}
+
+func verifyMessage(address, signature, message string, lenient bool) (bool, error) {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false, xerrors.Errorf("signature is not base64: %w", err)
+	}
+	if len(sig) != compactSigLen {
+		return false, xerrors.Errorf("signature must be %d bytes, got %d: %w", compactSigLen, len(sig), ErrInvalidCompactSigLength)
+	}
+	header := int(sig[0]) - compactSigMagic
+	if header < 0 || header > 15 {
+		return false, xerrors.Errorf("header %d: %w", sig[0], ErrInvalidRecoveryID)
+	}
+	addrType := AddressType(header / 4)
+	// rewrite the header into the plain compact form RecoverCompact knows.
+	compact := append([]byte{}, sig...)
+	compact[0] = compactSigMagic + byte(header&3)
+	if addrType != P2PKHUncompressed {
+		compact[0] += compactSigCompressed
+	}
+	pub, _, err := RecoverCompact(compact, MessageHash(message))
+	if err != nil {
+		return false, err
+	}
+	types := []AddressType{addrType}
+	if lenient && addrType != P2PKHUncompressed {
+		types = []AddressType{P2PKH, P2SHP2WPKH, P2WPKH}
+	}
+	for _, testnet := range []bool{false, true} {
+		for _, t := range types {
+			got := messageAddress(pub, t, testnet)
+			// bech32 addresses may also be written in upper case.
+			if got == address || (t == P2WPKH && got == strings.ToLower(address)) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// messageAddress returns the address of type addrType for pub.
+func messageAddress(pub *s256Point, addrType AddressType, testnet bool) string {
+	switch addrType {
+	case P2PKHUncompressed:
+		return pub.Addresses(false, testnet)
+	case P2PKH:
+		return pub.Addresses(true, testnet)
+	case P2SHP2WPKH:
+		return pub.P2SHP2WPKHAddress(testnet)
+	default:
+		return pub.P2WPKHAddress(testnet)
+	}
+}
@@ -0,0 +1,161 @@
+package ecc
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcutil"
+)
+
+func mustPrivateKeyFromWIF(t *testing.T, wif string) *PrivateKey {
+	t.Helper()
+	w, err := btcutil.DecodeWIF(wif)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPrivateKey(w.PrivKey.D)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPrivateKey_SignMessage(t *testing.T) {
+	// the example of bitcoinjs-message, which agrees with Bitcoin Core and
+	// Electrum.
+	p := mustPrivateKeyFromWIF(t, "L4rK1yDtCWekvXuE6oXD9jCYfFNV2cWRpVuPLBcCU2z8TrisoyY1")
+	message := "This is an example of a signed message."
+	tests := []struct {
+		name     string
+		addrType AddressType
+		address  string
+		want     string
+	}{
+		{
+			name:     "P2PKH uncompressed",
+			addrType: P2PKHUncompressed,
+			address:  p.p.Addresses(false, false),
+			want:     "G9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=",
+		},
+		{
+			name:     "P2PKH",
+			addrType: P2PKH,
+			address:  "1F3sAm6ZtwLAUnj7d38pGFxtP3RVEvtsbV",
+			want:     "H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=",
+		},
+		{
+			name:     "P2SH-P2WPKH",
+			addrType: P2SHP2WPKH,
+			address:  p.p.P2SHP2WPKHAddress(false),
+			want:     "I9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=",
+		},
+		{
+			name:     "P2WPKH",
+			addrType: P2WPKH,
+			address:  p.p.P2WPKHAddress(false),
+			want:     "J9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.SignMessage(message, tt.addrType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("PrivateKey.SignMessage() = %v, want %v", got, tt.want)
+			}
+			ok, err := VerifyMessage(tt.address, got, message)
+			if err != nil || !ok {
+				t.Errorf("VerifyMessage() = %v, %v, want true", ok, err)
+			}
+			if ok, _ := VerifyMessage(tt.address, got, message+"!"); ok {
+				t.Error("VerifyMessage() accepted another message")
+			}
+		})
+	}
+}
+
+func TestVerifyMessage(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := NewPrivateKey(big.NewInt(1486))
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := "hello"
+	compressed, err := p.SignMessage(message, P2PKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed, err := p.SignMessage(message, P2PKHUncompressed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	segwit, err := p.SignMessage(message, P2WPKH)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		address      string
+		signature    string
+		want         bool
+		wantElectrum bool
+		wantErr      error
+	}{
+		{name: "OK if testnet", address: p.p.Addresses(true, true), signature: compressed, want: true, wantElectrum: true},
+		{name: "OK if upper case bech32", address: strings.ToUpper(p.p.P2WPKHAddress(false)), signature: segwit, want: true, wantElectrum: true},
+		{name: "Electrum only if segwit address with a P2PKH header", address: p.p.P2WPKHAddress(false), signature: compressed, wantElectrum: true},
+		{name: "Electrum only if nested segwit address with a P2WPKH header", address: p.p.P2SHP2WPKHAddress(false), signature: segwit, wantElectrum: true},
+		{name: "Electrum only if P2PKH address with a P2WPKH header", address: p.p.Addresses(true, false), signature: segwit, wantElectrum: true},
+		{name: "False if compressed key for the uncompressed address", address: p.p.Addresses(false, false), signature: compressed},
+		{name: "False if uncompressed key for a segwit address", address: p.p.P2WPKHAddress(false), signature: uncompressed},
+		{name: "False if another key", address: other.p.Addresses(true, false), signature: compressed},
+		{name: "NG if not base64", address: p.p.Addresses(true, false), signature: "!!"},
+		{name: "NG if short", address: p.p.Addresses(true, false), signature: base64.StdEncoding.EncodeToString(make([]byte, 64)), wantErr: ErrInvalidCompactSigLength},
+		{name: "NG if header is out of range", address: p.p.Addresses(true, false), signature: base64.StdEncoding.EncodeToString(append([]byte{43}, make([]byte, 64)...)), wantErr: ErrInvalidRecoveryID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyMessage(tt.address, tt.signature, message)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyMessage() = %v, want %v (error %v)", got, tt.want, err)
+			}
+			got, err = VerifyMessageElectrum(tt.address, tt.signature, message)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyMessageElectrum() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.wantElectrum {
+				t.Errorf("VerifyMessageElectrum() = %v, want %v (error %v)", got, tt.wantElectrum, err)
+			}
+		})
+	}
+}
+
+func Test_appendVarint(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{n: 0, want: "00"},
+		{n: 0xfc, want: "fc"},
+		{n: 0xfd, want: "fdfd00"},
+		{n: 0xffff, want: "fdffff"},
+		{n: 0x10000, want: "fe00000100"},
+		{n: 0x100000000, want: "ff0000000001000000"},
+	}
+	for _, tt := range tests {
+		if got := appendVarint(nil, tt.n); string(got) != string(mustDecodeString(tt.want)) {
+			t.Errorf("appendVarint(%d) = %x, want %v", tt.n, got, tt.want)
+		}
+	}
+}
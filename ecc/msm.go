@@ -0,0 +1,70 @@
+package ecc
+
+import "math/bits"
+
+// MultiScalarMul returns the sum of scalars[i]*points[i] using Pippenger's
+// bucket method. Every window of c bits costs one addition per point plus
+// about 2**(c+1) additions for the buckets, so for many points it is much
+// cheaper than a separate multiplication for each of them.
+//
+// Like DoubleScalarMul it runs in variable time and is meant for public
+// inputs such as batch verification. It panics if the slices differ in
+// length.
+func MultiScalarMul(scalars []Scalar, points []*s256Point) *s256Point {
+	if len(scalars) != len(points) {
+		panic("ecc: MultiScalarMul needs as many scalars as points")
+	}
+	c := pippengerWindow(len(points))
+	buckets := make([]s256JacobianPoint, 1<<c-1)
+	var acc s256JacobianPoint
+	acc.setInfinity()
+	for w := (256+c-1)/c - 1; w >= 0; w-- {
+		for i := 0; i < c; i++ {
+			acc.double(&acc)
+		}
+		for i := range buckets {
+			buckets[i].setInfinity()
+		}
+		for i := range scalars {
+			if d := scalars[i].window(w*c, c); d != 0 {
+				buckets[d-1].addMixed(&buckets[d-1], points[i])
+			}
+		}
+		// sum of d*bucket[d] as a running sum from the top bucket down.
+		var running, sum s256JacobianPoint
+		running.setInfinity()
+		sum.setInfinity()
+		for i := len(buckets) - 1; i >= 0; i-- {
+			running.add(&running, &buckets[i])
+			sum.add(&sum, &running)
+		}
+		acc.add(&acc, &sum)
+	}
+	return newS256PointFromJacobian(&acc)
+}
+
+// pippengerWindow returns the window size in bits for n points, which
+// grows with log n.
+func pippengerWindow(n int) int {
+	switch {
+	case n < 8:
+		return 2
+	case n < 32:
+		return 3
+	}
+	c := bits.Len(uint(n)) * 2 / 3
+	if c > 16 {
+		c = 16
+	}
+	return c
+}
+
+// window returns the width bits of s starting at bit offset, width <= 64.
+func (s *Scalar) window(offset, width int) uint64 {
+	limb, shift := offset/64, uint(offset%64)
+	v := s.n[limb] >> shift
+	if shift != 0 && limb < 3 {
+		v |= s.n[limb+1] << (64 - shift)
+	}
+	return v & (1<<uint(width) - 1)
+}
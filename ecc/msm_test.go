@@ -0,0 +1,78 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestMultiScalarMul(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(1))
+	for _, size := range []int{0, 1, 2, 7, 8, 33, 200} {
+		scalars := make([]Scalar, size)
+		points := make([]*s256Point, size)
+		want := S256Infinity()
+		for i := range scalars {
+			switch i % 5 {
+			case 1:
+				// zero scalars and infinity contribute nothing.
+				points[i] = S256Infinity()
+				scalars[i].SetBig(new(big.Int).Rand(r, genN()))
+			case 2:
+				points[i] = g
+			case 3:
+				// the same point twice, so that a bucket doubles.
+				points[i] = points[i-1]
+				scalars[i] = scalars[i-1]
+				continue
+			default:
+				points[i] = g.SRMul(new(big.Int).Rand(r, genN()))
+			}
+			if i%7 != 0 {
+				scalars[i].SetBig(new(big.Int).Rand(r, genN()))
+			}
+		}
+		for i := range scalars {
+			want = want.Add(points[i].ScalarMul(&scalars[i]))
+		}
+		if got := MultiScalarMul(scalars, points); !got.Eq(want) {
+			t.Errorf("MultiScalarMul() of %d points = %v, want %v", size, got, want)
+		}
+	}
+}
+
+func TestScalar_window(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		k := new(big.Int).Rand(r, genN())
+		s := mustScalar(t, k)
+		for _, width := range []int{1, 5, 13, 16} {
+			for offset := 0; offset < 256; offset += width {
+				want := new(big.Int).Rsh(k, uint(offset))
+				want.And(want, big.NewInt(1<<uint(width)-1))
+				if got := s.window(offset, width); got != want.Uint64() {
+					t.Fatalf("Scalar(%x).window(%d, %d) = %x, want %x", k, offset, width, got, want)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMultiScalarMul(b *testing.B) {
+	g, _ := genG()
+	r := rand.New(rand.NewSource(1))
+	scalars := make([]Scalar, 256)
+	points := make([]*s256Point, 256)
+	for i := range scalars {
+		scalars[i].SetBig(new(big.Int).Rand(r, genN()))
+		points[i] = g.SRMul(new(big.Int).Rand(r, genN()))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MultiScalarMul(scalars, points)
+	}
+}
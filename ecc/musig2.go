@@ -0,0 +1,479 @@
+package ecc
+
+import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"sort"
+	"sync"
+
+	"golang.org/x/xerrors"
+)
+
+// BIP327 tags.
+const (
+	tagMuSig2KeyAggList  = "KeyAgg list"
+	tagMuSig2KeyAggCoeff = "KeyAgg coefficient"
+	tagMuSig2Aux         = "MuSig/aux"
+	tagMuSig2Nonce       = "MuSig/nonce"
+	tagMuSig2NonceCoef   = "MuSig/noncecoef"
+)
+
+const (
+	// musig2PubNonceLen is the size of a public nonce or an aggregate nonce:
+	// two compressed points.
+	musig2PubNonceLen = 66
+	// musig2PartialSigLen is the size of a partial signature.
+	musig2PartialSigLen = 32
+)
+
+// MuSig2KeyAggContext is the aggregate public key of a set of signers, with
+// the tweaks applied to it so far. It is immutable; Tweak returns a new
+// context.
+// https://github.com/bitcoin/bips/blob/master/bip-0327.mediawiki
+type MuSig2KeyAggContext struct {
+	pubKeys   [][]byte
+	listHash  [32]byte
+	secondKey []byte
+	q         *s256Point
+	// accumulated sign and tweak: Q = gacc*Q_0 + tacc*G.
+	gacc, tacc Scalar
+}
+
+// MuSig2KeySort returns the compressed public keys sorted in lexicographic
+// order, which makes the aggregate key independent of the order in which
+// the signers are listed.
+func MuSig2KeySort(pubKeys [][]byte) [][]byte {
+	sorted := make([][]byte, len(pubKeys))
+	copy(sorted, pubKeys)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
+
+// MuSig2KeyAgg aggregates the 33-byte compressed public keys of the signers
+// into Q = a_1*P_1 + ... + a_u*P_u. The order of pubKeys matters; sort them
+// with MuSig2KeySort for an order independent key. An invalid key is
+// reported as an InvalidContributionError.
+func MuSig2KeyAgg(pubKeys [][]byte) (*MuSig2KeyAggContext, error) {
+	if len(pubKeys) == 0 {
+		return nil, xerrors.New("no public keys to aggregate")
+	}
+	c := &MuSig2KeyAggContext{secondKey: make([]byte, 33)}
+	points := make([]*s256Point, len(pubKeys))
+	var list []byte
+	for i, pk := range pubKeys {
+		p, err := parseMuSig2Point(pk)
+		if err != nil {
+			return nil, &InvalidContributionError{Signer: i, Err: err}
+		}
+		points[i] = p
+		c.pubKeys = append(c.pubKeys, append([]byte(nil), pk...))
+		list = append(list, pk...)
+	}
+	c.listHash = taggedHash(tagMuSig2KeyAggList, list)
+	for _, pk := range c.pubKeys[1:] {
+		if !bytes.Equal(pk, c.pubKeys[0]) {
+			c.secondKey = pk
+			break
+		}
+	}
+	scalars := make([]Scalar, len(points))
+	for i, pk := range c.pubKeys {
+		scalars[i] = c.coefficient(pk)
+	}
+	c.q = MultiScalarMul(scalars, points)
+	if c.q.IsInfinity() {
+		return nil, xerrors.New("aggregate public key is infinity")
+	}
+	c.gacc = *NewScalar(1)
+	return c, nil
+}
+
+// coefficient returns the KeyAgg coefficient a_i of the key pk. The second
+// distinct key gets 1, which saves a multiplication.
+func (c *MuSig2KeyAggContext) coefficient(pk []byte) Scalar {
+	if bytes.Equal(pk, c.secondKey) {
+		return *NewScalar(1)
+	}
+	h := taggedHash(tagMuSig2KeyAggCoeff, c.listHash[:], pk)
+	var a Scalar
+	a.SetBytes(&h)
+	return a
+}
+
+func (c *MuSig2KeyAggContext) hasKey(pk []byte) bool {
+	for _, k := range c.pubKeys {
+		if bytes.Equal(k, pk) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicKey returns the aggregate public key Q with the tweaks applied. Its
+// XOnly encoding is the key BIP340 signatures verify against.
+func (c *MuSig2KeyAggContext) PublicKey() *s256Point {
+	return c.q
+}
+
+// Tweak returns the context for Q + t*G, where t is the 32-byte tweak. An
+// x-only tweak applies to the key with an even y instead, as taproot does:
+// Q' = evenY(Q) + t*G. Tweaks can be chained.
+func (c *MuSig2KeyAggContext) Tweak(tweak []byte, xOnly bool) (*MuSig2KeyAggContext, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	g := *NewScalar(1)
+	q := c.q
+	if xOnly && q.y.IsOdd() {
+		g.Negate(&g)
+		q = q.Negate()
+	}
+	out := *c
+	out.q = q.Add(ScalarBaseMul(&t))
+	if out.q.IsInfinity() {
+		return nil, xerrors.Errorf("tweaked public key is infinity: %w", ErrInvalidTweak)
+	}
+	out.gacc.Mul(&g, &c.gacc)
+	out.tacc.Mul(&g, &c.tacc).Add(&out.tacc, &t)
+	return &out, nil
+}
+
+// parseMuSig2Point decodes a 33-byte compressed point, the only encoding
+// BIP327 uses for keys and nonces.
+func parseMuSig2Point(b []byte) (*s256Point, error) {
+	if len(b) != 33 {
+		return nil, xerrors.Errorf("point must be 33 bytes, got %d: %w", len(b), ErrInvalidPubKeyLength)
+	}
+	return ParseSec(b)
+}
+
+// parseMuSig2PointExt is parseMuSig2Point with 33 zero bytes standing for
+// infinity.
+func parseMuSig2PointExt(b []byte) (*s256Point, error) {
+	if bytes.Equal(b, make([]byte, 33)) {
+		return S256Infinity(), nil
+	}
+	return parseMuSig2Point(b)
+}
+
+// secExt is Sec(true) with 33 zero bytes for infinity.
+func secExt(p *s256Point) []byte {
+	if p.IsInfinity() {
+		return make([]byte, 33)
+	}
+	return p.Sec(true)
+}
+
+// MuSig2NonceAgg sums the 66-byte public nonces of all signers into the
+// aggregate nonce every signer needs to sign. An invalid nonce is reported
+// as an InvalidContributionError.
+func MuSig2NonceAgg(pubNonces [][]byte) ([]byte, error) {
+	var acc [2]s256JacobianPoint
+	acc[0].setInfinity()
+	acc[1].setInfinity()
+	for i, nonce := range pubNonces {
+		if len(nonce) != musig2PubNonceLen {
+			return nil, &InvalidContributionError{Signer: i, Err: xerrors.Errorf("public nonce must be %d bytes, got %d", musig2PubNonceLen, len(nonce))}
+		}
+		for j := range acc {
+			r, err := parseMuSig2Point(nonce[33*j : 33*(j+1)])
+			if err != nil {
+				return nil, &InvalidContributionError{Signer: i, Err: err}
+			}
+			acc[j].addMixed(&acc[j], r)
+		}
+	}
+	out := secExt(newS256PointFromJacobian(&acc[0]))
+	return append(out, secExt(newS256PointFromJacobian(&acc[1]))...), nil
+}
+
+// musig2SecNonce is the secret half of a nonce: k1, k2 and the public key
+// it may be used with.
+type musig2SecNonce struct {
+	k1, k2 Scalar
+	pubKey []byte
+}
+
+// pubNonce returns k1*G || k2*G.
+func (n *musig2SecNonce) pubNonce() []byte {
+	return append(ScalarBaseMul(&n.k1).Sec(true), ScalarBaseMul(&n.k2).Sec(true)...)
+}
+
+// musig2NonceGen is NonceGen of BIP327. sk, aggPK, msg and extra are
+// optional and may be nil; msg is absent when nil, unlike an empty message.
+func musig2NonceGen(rand *[32]byte, sk *PrivateKey, pubKey, aggPK, msg, extra []byte) (*musig2SecNonce, error) {
+	seed := *rand
+	if sk != nil {
+		// rand = bytes(sk) xor hash_aux(rand')
+		aux := taggedHash(tagMuSig2Aux, rand[:])
+		seed = sk.secret.Bytes()
+		for i := range seed {
+			seed[i] ^= aux[i]
+		}
+	}
+	var data []byte
+	data = append(data, seed[:]...)
+	data = append(data, byte(len(pubKey)))
+	data = append(data, pubKey...)
+	data = append(data, byte(len(aggPK)))
+	data = append(data, aggPK...)
+	if msg == nil {
+		data = append(data, 0)
+	} else {
+		data = append(data, 1)
+		data = appendUint64BE(data, uint64(len(msg)))
+		data = append(data, msg...)
+	}
+	data = appendUint32BE(data, uint32(len(extra)))
+	data = append(data, extra...)
+
+	n := &musig2SecNonce{pubKey: append([]byte(nil), pubKey...)}
+	for i, k := range []*Scalar{&n.k1, &n.k2} {
+		h := taggedHash(tagMuSig2Nonce, data, []byte{byte(i)})
+		if k.SetBytes(&h); k.IsZero() {
+			return nil, xerrors.New("nonce is zero")
+		}
+	}
+	return n, nil
+}
+
+func appendUint32BE(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64BE(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// MuSig2Session is one signer's part in a single MuSig2 signing. It holds
+// the secret nonce, which never leaves the session and is erased by the
+// first call to Sign, so a nonce cannot be used twice. Start a new session
+// for every signature.
+type MuSig2Session struct {
+	key      *PrivateKey
+	keyAgg   *MuSig2KeyAggContext
+	pubNonce []byte
+
+	mu       sync.Mutex
+	secNonce *musig2SecNonce
+}
+
+// NewMuSig2Session generates a fresh nonce for p to sign for the aggregate
+// key of keyAgg, which must include p. rand defaults to crypto/rand; the
+// nonce also depends on the private key and the aggregate key, as BIP327
+// recommends, but never only on them.
+func (p *PrivateKey) NewMuSig2Session(keyAgg *MuSig2KeyAggContext, rand io.Reader) (*MuSig2Session, error) {
+	pk := p.p.Sec(true)
+	if !keyAgg.hasKey(pk) {
+		return nil, xerrors.New("private key is not one of the aggregated keys")
+	}
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	var r [32]byte
+	if _, err := io.ReadFull(rand, r[:]); err != nil {
+		return nil, xerrors.Errorf("reading nonce randomness: %w", err)
+	}
+	secNonce, err := musig2NonceGen(&r, p, pk, keyAgg.q.XOnly(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MuSig2Session{
+		key:      p,
+		keyAgg:   keyAgg,
+		pubNonce: secNonce.pubNonce(),
+		secNonce: secNonce,
+	}, nil
+}
+
+// PubNonce returns the 66-byte public nonce to send to the other signers.
+func (s *MuSig2Session) PubNonce() []byte {
+	return append([]byte(nil), s.pubNonce...)
+}
+
+// Sign returns the 32-byte partial signature of msg under the aggregate
+// nonce of all signers. The secret nonce is erased before anything else is
+// done, so every later call fails with ErrNonceUsed.
+func (s *MuSig2Session) Sign(aggNonce, msg []byte) ([]byte, error) {
+	s.mu.Lock()
+	secNonce := s.secNonce
+	s.secNonce = nil
+	s.mu.Unlock()
+	if secNonce == nil {
+		return nil, ErrNonceUsed
+	}
+	values, err := newMuSig2SessionValues(s.keyAgg, aggNonce, msg)
+	if err != nil {
+		return nil, err
+	}
+	psig, err := values.sign(secNonce, s.key)
+	// the nonce is only reachable from here on; clear it for good measure.
+	*secNonce = musig2SecNonce{}
+	return psig, err
+}
+
+// musig2SessionValues are the values every signer derives from the
+// aggregate key, the aggregate nonce and the message.
+type musig2SessionValues struct {
+	keyAgg *MuSig2KeyAggContext
+	// b is the nonce coefficient, r the final nonce point and e the
+	// BIP340 challenge.
+	b, e Scalar
+	r    *s256Point
+}
+
+func newMuSig2SessionValues(keyAgg *MuSig2KeyAggContext, aggNonce, msg []byte) (*musig2SessionValues, error) {
+	if len(aggNonce) != musig2PubNonceLen {
+		return nil, xerrors.Errorf("aggregate nonce must be %d bytes, got %d", musig2PubNonceLen, len(aggNonce))
+	}
+	r1, err := parseMuSig2PointExt(aggNonce[:33])
+	if err != nil {
+		return nil, xerrors.Errorf("aggregate nonce: %w", err)
+	}
+	r2, err := parseMuSig2PointExt(aggNonce[33:])
+	if err != nil {
+		return nil, xerrors.Errorf("aggregate nonce: %w", err)
+	}
+	v := &musig2SessionValues{keyAgg: keyAgg}
+	h := taggedHash(tagMuSig2NonceCoef, aggNonce, keyAgg.q.XOnly(), msg)
+	v.b.SetBytes(&h)
+	// R = R1 + b*R2, replaced by G in the unlikely case of infinity.
+	v.r = r1.Add(r2.ScalarMul(&v.b))
+	if v.r.IsInfinity() {
+		v.r, _ = genG()
+	}
+	v.e = schnorrChallenge(v.r.XOnly(), keyAgg.q.XOnly(), msg)
+	return v, nil
+}
+
+// keySign returns g*gacc, where g negates the secret keys when Q has an odd
+// y.
+func (v *musig2SessionValues) keySign() Scalar {
+	g := v.keyAgg.gacc
+	if v.keyAgg.q.y.IsOdd() {
+		g.Negate(&g)
+	}
+	return g
+}
+
+// sign computes s = k1 + b*k2 + e*a*d, with the nonces negated when R has
+// an odd y and d = g*gacc*secret.
+func (v *musig2SessionValues) sign(n *musig2SecNonce, key *PrivateKey) ([]byte, error) {
+	if n.k1.IsZero() || n.k2.IsZero() {
+		return nil, xerrors.New("secret nonce is zero")
+	}
+	pk := key.p.Sec(true)
+	if !bytes.Equal(pk, n.pubKey) {
+		return nil, xerrors.New("secret nonce belongs to another key")
+	}
+	if !v.keyAgg.hasKey(pk) {
+		return nil, xerrors.New("private key is not one of the aggregated keys")
+	}
+	pubNonce := n.pubNonce()
+	k1, k2 := n.k1, n.k2
+	if v.r.y.IsOdd() {
+		k1.Negate(&k1)
+		k2.Negate(&k2)
+	}
+	a := v.keyAgg.coefficient(pk)
+	d := v.keySign()
+	d.Mul(&d, &key.secret)
+
+	var s, t Scalar
+	s.Mul(&v.b, &k2).Add(&s, &k1)
+	t.Mul(&v.e, &a).Mul(&t, &d)
+	s.Add(&s, &t)
+	out := s.Bytes()
+
+	if ok, err := v.verifyPartial(out[:], pubNonce, pk); err != nil || !ok {
+		return nil, xerrors.New("created an invalid partial signature")
+	}
+	return out[:], nil
+}
+
+// verifyPartial checks s*G = Re + e*a*g*gacc*P, where Re is R1 + b*R2 of
+// the signer's own nonce, negated when R has an odd y.
+func (v *musig2SessionValues) verifyPartial(psig, pubNonce, pubKey []byte) (bool, error) {
+	if len(psig) != musig2PartialSigLen {
+		return false, xerrors.Errorf("partial signature must be %d bytes, got %d", musig2PartialSigLen, len(psig))
+	}
+	if len(pubNonce) != musig2PubNonceLen {
+		return false, xerrors.Errorf("public nonce must be %d bytes, got %d", musig2PubNonceLen, len(pubNonce))
+	}
+	if !v.keyAgg.hasKey(pubKey) {
+		return false, xerrors.New("public key is not one of the aggregated keys")
+	}
+	p, err := parseMuSig2Point(pubKey)
+	if err != nil {
+		return false, err
+	}
+	r1, err := parseMuSig2Point(pubNonce[:33])
+	if err != nil {
+		return false, xerrors.Errorf("public nonce: %w", err)
+	}
+	r2, err := parseMuSig2Point(pubNonce[33:])
+	if err != nil {
+		return false, xerrors.Errorf("public nonce: %w", err)
+	}
+	var s Scalar
+	if s.SetByteSlice(psig) {
+		return false, nil
+	}
+	re := r1.Add(r2.ScalarMul(&v.b))
+	if v.r.y.IsOdd() {
+		re = re.Negate()
+	}
+	// s*G - e*a*g*gacc*P must be Re.
+	a := v.keyAgg.coefficient(pubKey)
+	c := v.keySign()
+	c.Mul(&c, &a).Mul(&c, &v.e).Negate(&c)
+	return DoubleScalarMul(s.Big(), c.Big(), p).Eq(re), nil
+}
+
+// MuSig2PartialSigVerify reports whether psig is the partial signature of
+// msg by the signer with public key pubKey and public nonce pubNonce. The
+// aggregator uses it to find a signer that broke the final signature.
+func MuSig2PartialSigVerify(psig, pubNonce, pubKey []byte, keyAgg *MuSig2KeyAggContext, aggNonce, msg []byte) (bool, error) {
+	v, err := newMuSig2SessionValues(keyAgg, aggNonce, msg)
+	if err != nil {
+		return false, err
+	}
+	return v.verifyPartial(psig, pubNonce, pubKey)
+}
+
+// MuSig2PartialSigAgg combines the partial signatures of all signers into
+// a BIP340 signature of msg that verifies against the x-only aggregate
+// key. A partial signature out of range is reported as an
+// InvalidContributionError; one that is merely wrong makes the result
+// invalid, which MuSig2PartialSigVerify can pin down.
+func MuSig2PartialSigAgg(psigs [][]byte, keyAgg *MuSig2KeyAggContext, aggNonce, msg []byte) (*SchnorrSignature, error) {
+	v, err := newMuSig2SessionValues(keyAgg, aggNonce, msg)
+	if err != nil {
+		return nil, err
+	}
+	sig := &SchnorrSignature{r: *v.r.x}
+	for i, psig := range psigs {
+		var s Scalar
+		if len(psig) != musig2PartialSigLen || s.SetByteSlice(psig) {
+			return nil, &InvalidContributionError{Signer: i, Err: xerrors.New("partial signature is not a 32-byte scalar below n")}
+		}
+		sig.s.Add(&sig.s, &s)
+	}
+	// s += e*g*tacc, where g is the sign of Q.
+	var t Scalar
+	t.Mul(&v.e, &keyAgg.tacc)
+	if keyAgg.q.y.IsOdd() {
+		t.Negate(&t)
+	}
+	sig.s.Add(&sig.s, &t)
+	return sig, nil
+}
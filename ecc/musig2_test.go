@@ -0,0 +1,354 @@
+package ecc
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func mustHexList(t testing.TB, list ...string) [][]byte {
+	t.Helper()
+	out := make([][]byte, len(list))
+	for i, s := range list {
+		out[i] = mustHex(t, s)
+	}
+	return out
+}
+
+func pick(list [][]byte, indices ...int) [][]byte {
+	out := make([][]byte, len(indices))
+	for i, j := range indices {
+		out[i] = list[j]
+	}
+	return out
+}
+
+// the key_agg_vectors.json of BIP327.
+func TestMuSig2KeyAgg(t *testing.T) {
+	pubKeys := mustHexList(t,
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+		"020000000000000000000000000000000000000000000000000000000000000005",
+		"02FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEFFFFFC30",
+		"04F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+	)
+	tweaks := mustHexList(t,
+		"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFEBAAEDCE6AF48A03BBFD25E8CD0364141",
+		"252E4BD67410A76CDF933D30EAA1608214037F1B105A013ECCD3C5C184A6110B",
+	)
+	valid := []struct {
+		keys []int
+		want string
+	}{
+		{keys: []int{0, 1, 2}, want: "90539EEDE565F5D054F32CC0C220126889ED1E5D193BAF15AEF344FE59D4610C"},
+		{keys: []int{2, 1, 0}, want: "6204DE8B083426DC6EAF9502D27024D53FC826BF7D2012148A0575435DF54B2B"},
+		{keys: []int{0, 0, 0}, want: "B436E3BAD62B8CD409969A224731C193D051162D8C5AE8B109306127DA3AA935"},
+		{keys: []int{0, 0, 1, 1}, want: "69BC22BFA5D106306E48A20679DE1D7389386124D07571D0D872686028C26A3E"},
+	}
+	for _, tt := range valid {
+		c, err := MuSig2KeyAgg(pick(pubKeys, tt.keys...))
+		if err != nil {
+			t.Fatalf("MuSig2KeyAgg(%v) error = %v", tt.keys, err)
+		}
+		if got := c.PublicKey().XOnly(); !bytes.Equal(got, mustHex(t, tt.want)) {
+			t.Errorf("MuSig2KeyAgg(%v) = %X, want %v", tt.keys, got, tt.want)
+		}
+	}
+
+	invalid := []struct {
+		name       string
+		keys       []int
+		signer     int
+		tweak      int
+		tweakXOnly bool
+	}{
+		{name: "not on the curve", keys: []int{0, 3}, signer: 1},
+		{name: "exceeds field size", keys: []int{0, 4}, signer: 1},
+		{name: "uncompressed", keys: []int{5, 0}, signer: 0},
+		{name: "tweak out of range", keys: []int{0, 1}, signer: -1, tweak: 0, tweakXOnly: true},
+		{name: "tweaked key is infinity", keys: []int{6}, signer: -1, tweak: 1},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := MuSig2KeyAgg(pick(pubKeys, tt.keys...))
+			if tt.signer >= 0 {
+				var contrib *InvalidContributionError
+				if !errors.As(err, &contrib) || contrib.Signer != tt.signer {
+					t.Fatalf("MuSig2KeyAgg() error = %v, want signer %d blamed", err, tt.signer)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := c.Tweak(tweaks[tt.tweak], tt.tweakXOnly); err == nil {
+				t.Error("Tweak() succeeded")
+			}
+		})
+	}
+}
+
+func TestMuSig2KeySort(t *testing.T) {
+	pubKeys := mustHexList(t,
+		"02DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"03DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA659",
+		"023590A94E768F8E1815C2F24B4D80A8E3149316C3518CE7B7AD338368D038CA66",
+		"02DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EFF",
+		"02DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8",
+	)
+	want := pick(pubKeys, 3, 0, 5, 4, 1, 2)
+	got := MuSig2KeySort(pubKeys)
+	for i := range want {
+		if !bytes.Equal(got[i], want[i]) {
+			t.Fatalf("MuSig2KeySort()[%d] = %X, want %X", i, got[i], want[i])
+		}
+	}
+	if !bytes.Equal(pubKeys[0], mustHex(t, "02DD308AFEC5777E13121FA72B9CC1B7CC0139715309B086C960E18FD969774EB8")) {
+		t.Error("MuSig2KeySort() modified its input")
+	}
+}
+
+// the sign_verify_vectors.json of BIP327.
+func TestMuSig2_signVectors(t *testing.T) {
+	key := mustPrivateKeyFromHex(t, "7FB9E0E687ADA1EEBF7ECFE2F21E73EBDB51A7D450948DFE8D76D7F2D1007671")
+	pubKeys := mustHexList(t,
+		"03935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9",
+		"02F9308A019258C31049344F85F89D5229B531C845836F99B08601F113BCE036F9",
+		"02DFF1D77F2A671C5F36183726DB2341BE58FEAE1DA2DECED843240F7B502BA661",
+	)
+	secNonce := mustHex(t, "508B81A611F100A6B2B6B29656590898AF488BCF2E1F55CF22E5CFB84421FE61FA27FD49B1D50085B481285E1CA205D55C82CC1B31FF5CD54A489829355901F703935F972DA013F80AE011890FA89B67A27B7BE6CCB24D3274D18B2D4067F261A9")
+	pubNonces := mustHexList(t,
+		"0337C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0287BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+		"0279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F817980279BE667EF9DCBBAC55A06295CE870B07029BFCDB2DCE28D959F2815B16F81798",
+		"032DE2662628C90B03F5E720284EB52FF7D71F4284F627B68A853D78C78E1FFE9303E4C5524E83FFE1493B9077CF1CA6BEB2090C93D930321071AD40B2F44E599046",
+		"0237C87821AFD50A8644D820A8F3E02E499C931865C2360FB43D0A0D20DAFE07EA0387BF891D2A6DEAEBADC909352AA9405D1428C15F4B75F04DAE642A95C2548480",
+	)
+	aggNonces := mustHexList(t,
+		"028465FCF0BBDBCF443AABCCE533D42B4B5A10966AC09A49655E8C42DAAB8FCD61037496A3CC86926D452CAFCFD55D25972CA1675D549310DE296BFF42F72EEEA8C9",
+		strings.Repeat("00", 66),
+	)
+	msg := mustHex(t, "F95466D086770E689964664219266FE5ED215C92AE20BAB5C9D79ADDDDF3C0CF")
+
+	n := &musig2SecNonce{pubKey: secNonce[64:]}
+	n.k1.SetByteSlice(secNonce[:32])
+	n.k2.SetByteSlice(secNonce[32:64])
+	if !bytes.Equal(n.pubNonce(), pubNonces[0]) {
+		t.Fatalf("pubNonce() = %X, want %X", n.pubNonce(), pubNonces[0])
+	}
+
+	tests := []struct {
+		keys     []int
+		nonces   []int
+		aggNonce int
+		want     string
+	}{
+		{keys: []int{0, 1, 2}, nonces: []int{0, 1, 2}, aggNonce: 0, want: "012ABBCB52B3016AC03AD82395A1A415C48B93DEF78718E62A7A90052FE224FB"},
+		{keys: []int{1, 0, 2}, nonces: []int{1, 0, 2}, aggNonce: 0, want: "9FF2F7AAA856150CC8819254218D3ADEEB0535269051897724F9DB3789513A52"},
+		{keys: []int{1, 2, 0}, nonces: []int{1, 2, 0}, aggNonce: 0, want: "FA23C359F6FAC4E7796BB93BC9F0532A95468C539BA20FF86D7C76ED92227900"},
+		// the aggregate nonce is infinity.
+		{keys: []int{0, 1}, nonces: []int{0, 3}, aggNonce: 1, want: "AE386064B26105404798F75DE2EB9AF5EDA5387B064B83D049CB7C5E08879531"},
+	}
+	for _, tt := range tests {
+		gotAgg, err := MuSig2NonceAgg(pick(pubNonces, tt.nonces...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(gotAgg, aggNonces[tt.aggNonce]) {
+			t.Errorf("MuSig2NonceAgg(%v) = %X, want %X", tt.nonces, gotAgg, aggNonces[tt.aggNonce])
+		}
+		keyAgg, err := MuSig2KeyAgg(pick(pubKeys, tt.keys...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := newMuSig2SessionValues(keyAgg, aggNonces[tt.aggNonce], msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := v.sign(n, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, mustHex(t, tt.want)) {
+			t.Errorf("sign(%v) = %X, want %v", tt.keys, got, tt.want)
+		}
+		ok, err := MuSig2PartialSigVerify(got, pubNonces[0], pubKeys[0], keyAgg, aggNonces[tt.aggNonce], msg)
+		if err != nil || !ok {
+			t.Errorf("MuSig2PartialSigVerify() = %v, %v, want true", ok, err)
+		}
+	}
+}
+
+func mustPrivateKeyFromHex(t testing.TB, s string) *PrivateKey {
+	t.Helper()
+	p, err := NewPrivateKey(new(big.Int).SetBytes(mustHex(t, s)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+// musig2Sign runs a whole signing round of keys and returns the signature.
+func musig2Sign(t *testing.T, keys []*PrivateKey, keyAgg *MuSig2KeyAggContext, msg []byte) *SchnorrSignature {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	sessions := make([]*MuSig2Session, len(keys))
+	pubNonces := make([][]byte, len(keys))
+	for i, key := range keys {
+		s, err := key.NewMuSig2Session(keyAgg, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sessions[i] = s
+		pubNonces[i] = s.PubNonce()
+	}
+	aggNonce, err := MuSig2NonceAgg(pubNonces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	psigs := make([][]byte, len(keys))
+	for i, s := range sessions {
+		if psigs[i], err = s.Sign(aggNonce, msg); err != nil {
+			t.Fatal(err)
+		}
+		ok, err := MuSig2PartialSigVerify(psigs[i], pubNonces[i], keys[i].p.Sec(true), keyAgg, aggNonce, msg)
+		if err != nil || !ok {
+			t.Fatalf("MuSig2PartialSigVerify(%d) = %v, %v, want true", i, ok, err)
+		}
+	}
+	sig, err := MuSig2PartialSigAgg(psigs, keyAgg, aggNonce, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sig
+}
+
+func TestMuSig2(t *testing.T) {
+	var keys []*PrivateKey
+	var pubKeys [][]byte
+	for _, secret := range []int64{3, 1000, 123456789} {
+		key, err := NewPrivateKey(big.NewInt(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		pubKeys = append(pubKeys, key.p.Sec(true))
+	}
+	keyAgg, err := MuSig2KeyAgg(MuSig2KeySort(pubKeys))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweak := func(c *MuSig2KeyAggContext, b byte, xOnly bool) *MuSig2KeyAggContext {
+		out, err := c.Tweak(bytes.Repeat([]byte{b}, 32), xOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+	g, _ := genG()
+	t1 := new(big.Int).SetBytes(bytes.Repeat([]byte{0x11}, 32))
+	t2 := new(big.Int).SetBytes(bytes.Repeat([]byte{0x22}, 32))
+	tests := []struct {
+		name   string
+		keyAgg *MuSig2KeyAggContext
+		want   *s256Point
+	}{
+		{name: "untweaked", keyAgg: keyAgg, want: keyAgg.PublicKey()},
+		{name: "plain tweak", keyAgg: tweak(keyAgg, 0x11, false), want: keyAgg.PublicKey().Add(g.SRMul(t1))},
+		{name: "x-only tweak", keyAgg: tweak(keyAgg, 0x11, true), want: keyAgg.PublicKey().evenY().Add(g.SRMul(t1))},
+		{
+			name:   "chained tweaks",
+			keyAgg: tweak(tweak(keyAgg, 0x11, true), 0x22, false),
+			want:   keyAgg.PublicKey().evenY().Add(g.SRMul(t1)).Add(g.SRMul(t2)),
+		},
+		{
+			name:   "chained x-only tweaks",
+			keyAgg: tweak(tweak(keyAgg, 0x11, false), 0x22, true),
+			want:   keyAgg.PublicKey().Add(g.SRMul(t1)).evenY().Add(g.SRMul(t2)),
+		},
+	}
+	msg := []byte("custody withdrawal")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.keyAgg.PublicKey().Eq(tt.want) {
+				t.Fatalf("PublicKey() = %v, want %v", tt.keyAgg.PublicKey(), tt.want)
+			}
+			sig := musig2Sign(t, keys, tt.keyAgg, msg)
+			pub, err := ParseXOnly(tt.want.XOnly())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok, err := pub.VerifySchnorr(msg, sig); err != nil || !ok {
+				t.Errorf("VerifySchnorr() = %v, %v, want true", ok, err)
+			}
+		})
+	}
+}
+
+func TestMuSig2Session(t *testing.T) {
+	a, _ := NewPrivateKey(big.NewInt(3))
+	b, _ := NewPrivateKey(big.NewInt(1000))
+	keyAgg, err := MuSig2KeyAgg([][]byte{a.p.Sec(true), b.p.Sec(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sa, err := a.NewMuSig2Session(keyAgg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sb, err := b.NewMuSig2Session(keyAgg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(sa.PubNonce(), sb.PubNonce()) {
+		t.Fatal("two sessions have the same nonce")
+	}
+	aggNonce, err := MuSig2NonceAgg([][]byte{sa.PubNonce(), sb.PubNonce()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg := []byte("once")
+	psigA, err := sa.Sign(aggNonce, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sa.Sign(aggNonce, []byte("twice")); !errors.Is(err, ErrNonceUsed) {
+		t.Errorf("second Sign() error = %v, want %v", err, ErrNonceUsed)
+	}
+
+	// a failed attempt uses up the nonce as well.
+	if _, err := sb.Sign(aggNonce[:65], msg); err == nil {
+		t.Fatal("Sign() accepted a short aggregate nonce")
+	}
+	if _, err := sb.Sign(aggNonce, msg); !errors.Is(err, ErrNonceUsed) {
+		t.Errorf("Sign() after a failure error = %v, want %v", err, ErrNonceUsed)
+	}
+
+	// a partial signature for the wrong signer or message does not verify.
+	if ok, _ := MuSig2PartialSigVerify(psigA, sa.PubNonce(), b.p.Sec(true), keyAgg, aggNonce, msg); ok {
+		t.Error("MuSig2PartialSigVerify() accepted the wrong public key")
+	}
+	if ok, _ := MuSig2PartialSigVerify(psigA, sa.PubNonce(), a.p.Sec(true), keyAgg, aggNonce, []byte("other")); ok {
+		t.Error("MuSig2PartialSigVerify() accepted the wrong message")
+	}
+
+	outsider, _ := NewPrivateKey(big.NewInt(5))
+	if _, err := outsider.NewMuSig2Session(keyAgg, nil); err == nil {
+		t.Error("NewMuSig2Session() accepted a key outside the aggregate")
+	}
+
+	bad := append(sa.PubNonce()[:33], make([]byte, 33)...)
+	var contrib *InvalidContributionError
+	if _, err := MuSig2NonceAgg([][]byte{sb.PubNonce(), bad}); !errors.As(err, &contrib) || contrib.Signer != 1 {
+		t.Errorf("MuSig2NonceAgg() error = %v, want signer 1 blamed", err)
+	}
+	n := genN().Bytes()
+	if _, err := MuSig2PartialSigAgg([][]byte{psigA, n}, keyAgg, aggNonce, msg); !errors.As(err, &contrib) || contrib.Signer != 1 {
+		t.Errorf("MuSig2PartialSigAgg() error = %v, want signer 1 blamed", err)
+	}
+}
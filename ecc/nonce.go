@@ -0,0 +1,96 @@
+package ecc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"math/big"
+)
+
+// rfc6979 generates the deterministic ECDSA nonces of RFC 6979 with
+// HMAC-SHA256 for a group of order q. Successive calls to next continue the
+// HMAC_DRBG as described in section 3.2 step h.3, which is what signers use
+// when a candidate nonce leads to an invalid signature.
+type rfc6979 struct {
+	q       *big.Int
+	qlen    int
+	k, v    []byte
+	started bool
+}
+
+// newRFC6979 seeds the generator with the private key x and the message
+// hash. extra, when not empty, is appended to the seed as the additional
+// data k' of section 3.6.
+func newRFC6979(q, x *big.Int, hash, extra []byte) *rfc6979 {
+	r := &rfc6979{q: q, qlen: q.BitLen()}
+	r.v = bytesRepeat(0x01, sha256.Size)
+	r.k = bytesRepeat(0x00, sha256.Size)
+
+	seed := append(r.int2octets(x), r.bits2octets(hash)...)
+	seed = append(seed, extra...)
+
+	r.k = r.mac(r.v, []byte{0x00}, seed)
+	r.v = r.mac(r.v)
+	r.k = r.mac(r.v, []byte{0x01}, seed)
+	r.v = r.mac(r.v)
+	return r
+}
+
+func bytesRepeat(b byte, n int) []byte {
+	out := make([]byte, n)
+	for i := range out {
+		out[i] = b
+	}
+	return out
+}
+
+func (r *rfc6979) mac(data ...[]byte) []byte {
+	h := hmac.New(sha256.New, r.k)
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// next returns the next candidate nonce in [1, q-1].
+func (r *rfc6979) next() *big.Int {
+	for {
+		if r.started {
+			r.k = r.mac(r.v, []byte{0x00})
+			r.v = r.mac(r.v)
+		}
+		r.started = true
+		var t []byte
+		for len(t)*8 < r.qlen {
+			r.v = r.mac(r.v)
+			t = append(t, r.v...)
+		}
+		k := r.bits2int(t)
+		if k.Sign() > 0 && k.Cmp(r.q) < 0 {
+			return k
+		}
+	}
+}
+
+// bits2int takes the leftmost qlen bits of b as an integer.
+func (r *rfc6979) bits2int(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - r.qlen; excess > 0 {
+		v.Rsh(v, uint(excess))
+	}
+	return v
+}
+
+// int2octets encodes x in exactly ceil(qlen/8) bytes.
+func (r *rfc6979) int2octets(x *big.Int) []byte {
+	out := make([]byte, (r.qlen+7)/8)
+	return x.FillBytes(out)
+}
+
+// bits2octets reduces the hash modulo q before encoding it.
+func (r *rfc6979) bits2octets(hash []byte) []byte {
+	z := r.bits2int(hash)
+	if z.Cmp(r.q) >= 0 {
+		z.Sub(z, r.q)
+	}
+	return r.int2octets(z)
+}
@@ -0,0 +1,58 @@
+package ecc
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1"
+)
+
+func Test_rfc6979(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  *big.Int
+		message string
+		want    *big.Int
+	}{
+		{
+			name:    "Satoshi Nakamoto",
+			secret:  big.NewInt(1),
+			message: "Satoshi Nakamoto",
+			want:    mustGetFromHex("0x8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15"),
+		},
+		{
+			name:    "All toys",
+			secret:  mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"),
+			message: "Satoshi Nakamoto",
+			want:    mustGetFromHex("0x33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash := sha256.Sum256([]byte(tt.message))
+			got := newRFC6979(genN(), tt.secret, hash[:], nil).next()
+			if got.Cmp(tt.want) != 0 {
+				t.Errorf("rfc6979.next() = %x, want %x", got, tt.want)
+			}
+			if ref := secp256k1.NonceRFC6979(tt.secret, hash[:], nil, nil); got.Cmp(ref) != 0 {
+				t.Errorf("rfc6979.next() = %x, secp256k1.NonceRFC6979() = %x", got, ref)
+			}
+		})
+	}
+}
+
+func Test_rfc6979_extra(t *testing.T) {
+	hash := sha256.Sum256([]byte("extra entropy"))
+	extra := sha256.Sum256([]byte("aux"))
+	secret := big.NewInt(1485)
+	got := newRFC6979(genN(), secret, hash[:], extra[:]).next()
+	if ref := secp256k1.NonceRFC6979(secret, hash[:], extra[:], nil); got.Cmp(ref) != 0 {
+		t.Errorf("rfc6979.next() = %x, secp256k1.NonceRFC6979() = %x", got, ref)
+	}
+	plain := newRFC6979(genN(), secret, hash[:], nil)
+	first, second := plain.next(), plain.next()
+	if first.Cmp(second) == 0 || first.Cmp(got) == 0 {
+		t.Error("rfc6979 should produce distinct nonces for retries and extra data")
+	}
+}
@@ -0,0 +1,130 @@
+package ecc
+
+import (
+	"math/big"
+	"sync"
+)
+
+const (
+	// baseWindowBits is the width of the fixed windows the scalar is split into.
+	baseWindowBits = 4
+	// baseWindows is the number of windows needed to cover a 256-bit scalar.
+	baseWindows = 256 / baseWindowBits
+	// baseWindowEntries is the number of non-zero digits in a window.
+	baseWindowEntries = 1<<baseWindowBits - 1
+)
+
+// s256AffineEntry is one precomputed multiple of G.
+type s256AffineEntry struct {
+	x, y s256FieldElement
+}
+
+var (
+	baseTableOnce sync.Once
+	// baseTable[i][j] holds (j+1) * 16**i * G in affine coordinates, so that
+	// k*G is the sum of one entry per window with no doublings at all.
+	baseTable *[baseWindows][baseWindowEntries]s256AffineEntry
+)
+
+// getBaseTable builds the generator table on first use and returns it. The
+// table is shared by every goroutine in the process.
+func getBaseTable() *[baseWindows][baseWindowEntries]s256AffineEntry {
+	baseTableOnce.Do(func() {
+		g, err := genG()
+		if err != nil {
+			// G is a constant, so this can only fail if the source is broken.
+			panic(err)
+		}
+		var points [baseWindows * baseWindowEntries]s256JacobianPoint
+		var base s256JacobianPoint
+		base.setAffine(g)
+		for i := 0; i < baseWindows; i++ {
+			row := points[i*baseWindowEntries : (i+1)*baseWindowEntries]
+			row[0] = base
+			for j := 1; j < baseWindowEntries; j++ {
+				row[j].add(&row[j-1], &base)
+			}
+			// the next window starts at 16 * base = 15 * base + base.
+			base.add(&row[baseWindowEntries-1], &base)
+		}
+		table := new([baseWindows][baseWindowEntries]s256AffineEntry)
+		normalizeS256Batch(points[:], func(i int, x, y *s256FieldElement) {
+			table[i/baseWindowEntries][i%baseWindowEntries] = s256AffineEntry{*x, *y}
+		})
+		baseTable = table
+	})
+	return baseTable
+}
+
+// normalizeS256Batch converts non-infinite Jacobian points to affine with a
+// single field inversion (Montgomery's trick) and hands each result to set.
+func normalizeS256Batch(points []s256JacobianPoint, set func(i int, x, y *s256FieldElement)) {
+	if len(points) == 0 {
+		return
+	}
+	// prefix[i] = z0 * z1 * ... * zi
+	prefix := make([]s256FieldElement, len(points))
+	prefix[0] = points[0].z
+	for i := 1; i < len(points); i++ {
+		prefix[i].Mul(&prefix[i-1], &points[i].z)
+	}
+	var inv s256FieldElement
+	inv.Inverse(&prefix[len(points)-1])
+	for i := len(points) - 1; i >= 0; i-- {
+		// zInv = (z0 * ... * zi)**-1 * (z0 * ... * zi-1)
+		var zInv, zInv2, zInv3, x, y s256FieldElement
+		if i > 0 {
+			zInv.Mul(&inv, &prefix[i-1])
+			inv.Mul(&inv, &points[i].z)
+		} else {
+			zInv = inv
+		}
+		zInv2.Square(&zInv)
+		zInv3.Mul(&zInv2, &zInv)
+		x.Mul(&points[i].x, &zInv2)
+		y.Mul(&points[i].y, &zInv3)
+		set(i, &x, &y)
+	}
+}
+
+// baseMul returns k * G for any integer k.
+func baseMul(k *big.Int) *s256Point {
+	var s Scalar
+	s.SetBig(k)
+	return ScalarBaseMul(&s)
+}
+
+// ScalarBaseMul returns k * G using the precomputed generator table. Every
+// window reads all of its entries and the additions use addMixedConst, so
+// neither the memory access pattern nor the branches depend on k.
+func ScalarBaseMul(k *Scalar) *s256Point {
+	kb := k.Bytes()
+
+	table := getBaseTable()
+	var result, sum s256JacobianPoint
+	result.setInfinity()
+	for i := 0; i < baseWindows; i++ {
+		digit := uint64(kb[31-i/2]>>(baseWindowBits*uint(i%2))) & baseWindowEntries
+		var entry s256Point
+		var x, y s256FieldElement
+		for j := 0; j < baseWindowEntries; j++ {
+			hit := ctEqual64(uint64(j+1), digit)
+			x.selectFrom(&table[i][j].x, &x, hit)
+			y.selectFrom(&table[i][j].y, &y, hit)
+		}
+		entry.x, entry.y = &x, &y
+		sum.addMixedConst(&result, &entry)
+		// a zero digit keeps the previous sum.
+		nonZero := ctEqual64(digit, 0) ^ 1
+		result.x.selectFrom(&sum.x, &result.x, nonZero)
+		result.y.selectFrom(&sum.y, &result.y, nonZero)
+		result.z.selectFrom(&sum.z, &result.z, nonZero)
+	}
+	return newS256PointFromJacobian(&result)
+}
+
+// ctEqual64 returns 1 if a == b and 0 otherwise without branching.
+func ctEqual64(a, b uint64) uint64 {
+	d := a ^ b
+	return ((d | -d) >> 63) ^ 1
+}
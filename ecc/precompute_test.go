@@ -0,0 +1,76 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func Test_baseMul(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := genN()
+	coefficients := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(15),
+		big.NewInt(16),
+		big.NewInt(1485),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Set(n),
+		new(big.Int).Add(n, big.NewInt(7)),
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		coefficients = append(coefficients, new(big.Int).Rand(r, n))
+	}
+	for _, k := range coefficients {
+		want := g.SRMul(k)
+		if got := baseMul(k); !got.Eq(want) {
+			t.Errorf("baseMul(%x) = x:%v y:%v want x:%v y:%v", k, got.x, got.y, want.x, want.y)
+		}
+	}
+}
+
+func Test_baseMul_concurrent(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := g.SRMul(big.NewInt(42424242))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := baseMul(big.NewInt(42424242)); !got.Eq(want) {
+				t.Errorf("baseMul() = x:%v y:%v want x:%v y:%v", got.x, got.y, want.x, want.y)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkBaseMul(b *testing.B) {
+	k := mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5")
+	getBaseTable()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		baseMul(k)
+	}
+}
+
+func BenchmarkSRMulG(b *testing.B) {
+	k := mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5")
+	g, err := genG()
+	if err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < b.N; i++ {
+		g.SRMul(k)
+	}
+}
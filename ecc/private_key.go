@@ -1,52 +1,125 @@
 package ecc
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"io"
 	"math/big"
 
-	"github.com/decred/dcrd/dcrec/secp256k1"
+	"golang.org/x/xerrors"
 )
 
 type PrivateKey struct {
 	p      *s256Point
-	secret *big.Int
+	secret Scalar
 }
 
+// NewPrivateKey returns the private key for secret, which must be in
+// [1, n-1].
 func NewPrivateKey(secret *big.Int) (*PrivateKey, error) {
 	p := &PrivateKey{}
-	p.secret = new(big.Int).Set(secret)
-	g, err := genG()
-	if err != nil {
-		return nil, err
-	}
-	err = g.SRMul(secret)
-	if err != nil {
-		return nil, err
+	if p.secret.SetBig(secret) || p.secret.IsZero() {
+		return nil, xerrors.New("private key is out of range")
 	}
-	p.p = g
+	p.p = ScalarBaseMul(&p.secret)
 	return p, nil
 }
 
+// SignHash returns a deterministic ECDSA signature of hash with the nonce
+// of RFC 6979. The signature is always low-S, as BIP146 requires for
+// standard transactions. It was called Sign before PrivateKey implemented
+// crypto.Signer.
 // https://github.com/btcsuite/btcd/blob/master/btcec/signature.go#L440
-func (p *PrivateKey) Sign(hash []byte) (*Signature, error) {
-	k := secp256k1.NonceRFC6979(p.secret, hash, nil, nil)
-	inv := new(big.Int).ModInverse(k, p.p.n)
-	g, err := genG()
-	if err != nil {
-		return nil, err
+func (p *PrivateKey) SignHash(hash []byte) (*Signature, error) {
+	return p.SignHashWithEntropy(hash, nil)
+}
+
+// SignHashWithEntropy is SignHash with extra data mixed into the RFC 6979
+// nonce as described in section 3.6. extra is either empty or 32 bytes,
+// like the ndata argument of libsecp256k1. Different extra values give
+// different, equally valid signatures.
+func (p *PrivateKey) SignHashWithEntropy(hash, extra []byte) (*Signature, error) {
+	sig, _, err := p.sign(hash, extra)
+	return sig, err
+}
+
+// sign returns the low-S signature of hash together with its recovery id:
+// bit 0 is the parity of the y of k*G and bit 1 is set when its x is not
+// below n.
+func (p *PrivateKey) sign(hash, extra []byte) (*Signature, byte, error) {
+	if len(extra) != 0 && len(extra) != 32 {
+		return nil, 0, xerrors.Errorf("extra entropy must be 32 bytes, got %d", len(extra))
+	}
+	var z Scalar
+	z.SetBig(Secp256k1().hashToInt(hash))
+	nonces := newRFC6979(genN(), p.secret.Big(), hash, extra)
+	for {
+		var k, r, s Scalar
+		k.SetBig(nonces.next())
+		// r = (k*G).x
+		kG := ScalarBaseMul(&k)
+		var recoveryID byte
+		if r.SetBig(kG.x.Big()) {
+			recoveryID |= 2
+		}
+		if kG.y.IsOdd() {
+			recoveryID |= 1
+		}
+		if r.IsZero() {
+			continue
+		}
+		// s = (z + r*secret) / k
+		s.Mul(&r, &p.secret).Add(&s, &z).Mul(&s, new(Scalar).Inverse(&k))
+		if s.IsZero() {
+			continue
+		}
+		// negating s is the same as signing with -k, whose y has the other parity.
+		if s.IsHigh() {
+			s.Negate(&s)
+			recoveryID ^= 1
+		}
+		return NewSignature(r.Big(), s.Big()), recoveryID, nil
 	}
-	err = g.SRMul(k)
+}
+
+// SignHashLowR grinds the extra entropy the way Bitcoin Core does until r
+// is below 2**255, which makes the DER signature one byte shorter. It tries
+// no extra data first, then a little-endian counter 1, 2, ... in the first
+// four bytes of a 32-byte extra value. About two attempts are needed on
+// average.
+func (p *PrivateKey) SignHashLowR(hash []byte) (*Signature, error) {
+	var extra [32]byte
+	sig, err := p.SignHashWithEntropy(hash, nil)
+	for counter := uint32(1); err == nil && sig.r.BitLen() > 255; counter++ {
+		binary.LittleEndian.PutUint32(extra[:], counter)
+		sig, err = p.SignHashWithEntropy(hash, extra[:])
+	}
+	return sig, err
+}
+
+// Public returns the public key of p as a *PublicKey.
+func (p *PrivateKey) Public() crypto.PublicKey {
+	return p.p.ToECDSA()
+}
+
+// Sign implements crypto.Signer. It signs digest with SignHash and returns
+// the DER encoded signature. Nonces are derived with RFC 6979, so rand is
+// not used. If opts names a hash function, digest must have its size.
+func (p *PrivateKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts != nil && opts.HashFunc() != 0 && len(digest) != opts.HashFunc().Size() {
+		return nil, xerrors.Errorf("digest is %d bytes, %v needs %d", len(digest), opts.HashFunc(), opts.HashFunc().Size())
+	}
+	sig, err := p.SignHash(digest)
 	if err != nil {
 		return nil, err
 	}
-	r := g.n
-	rMulSec := big.NewInt(0).Mul(r, p.secret)
-	zRMulSec := big.NewInt(0).Add(rMulSec, big.NewInt(0).SetBytes(hash))
-	zRMulSecMulKinv := big.NewInt(0).Mul(zRMulSec, inv)
-	zRMulSecMulKinv = zRMulSecMulKinv.Mod(zRMulSecMulKinv, p.p.n)
-	if big.NewInt(0).Div(p.p.n, big.NewInt(2)).Cmp(zRMulSecMulKinv) == -1 {
-		zRMulSecMulKinv = zRMulSecMulKinv.Sub(p.p.n, zRMulSecMulKinv)
-	}
-	return NewSignature(r, zRMulSecMulKinv), nil
+	return sig.Der(), nil
+}
+
+// ToECDSA returns p as an ecdsa.PrivateKey on S256().
+func (p *PrivateKey) ToECDSA() *ecdsa.PrivateKey {
+	return &ecdsa.PrivateKey{PublicKey: *p.p.ToECDSA(), D: p.secret.Big()}
 }
 
 func (p *PrivateKey) Wif(string, error) {
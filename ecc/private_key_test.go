@@ -1,13 +1,17 @@
 package ecc
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/sha256"
 	"math/big"
 	"testing"
 
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
 )
 
-func TestPrivateKey_Sign(t *testing.T) {
+func TestPrivateKey_SignHash(t *testing.T) {
 	tests := []struct {
 		name    string
 		secret  *big.Int
@@ -19,22 +23,159 @@ func TestPrivateKey_Sign(t *testing.T) {
 			secret: big.NewInt(10),
 			z:      chainhash.DoubleHashB([]byte("test message")),
 		},
+		{
+			name:   "OK if secret is 1",
+			secret: big.NewInt(1),
+			z:      chainhash.DoubleHashB([]byte("Satoshi Nakamoto")),
+		},
+		{
+			name:   "OK if secret is n - 1",
+			secret: mustGetFromHex("0xfffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140"),
+			z:      chainhash.DoubleHashB([]byte("Satoshi Nakamoto")),
+		},
+		{
+			name:   "OK if hash is zero",
+			secret: mustGetFromHex("0x8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15"),
+			z:      make([]byte, 32),
+		},
+		{
+			name:   "OK if hash is not below n",
+			secret: big.NewInt(12345),
+			z:      mustDecodeString("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p, _ := NewPrivateKey(tt.secret)
-			got, err := p.Sign(tt.z)
+			p, err := NewPrivateKey(tt.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := p.SignHash(tt.z)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("PrivateKey.Sign() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("PrivateKey.SignHash() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			t.Logf("Signature R: %x\n", got.r.String())
-			t.Logf("Signature S: %x\n", got.s.String())
-			// /Users/bruwbird/go/1.15.0/pkg/mod/github.com/btcsuite/btcd@v0.20.1-beta/btcec/signature.goをみる
 			ok, _ := p.p.Verify(big.NewInt(0).SetBytes(tt.z), *got)
 			if !ok {
-				t.Errorf("PrivateKey.Sign() failed")
+				t.Errorf("PrivateKey.SignHash() failed")
+			}
+			if got.s.Cmp(new(big.Int).Rsh(genN(), 1)) > 0 {
+				t.Errorf("PrivateKey.SignHash() s = %x is not low", got.s)
+			}
+			// btcec also signs with RFC 6979 and low-S, so the results must match.
+			ref, _ := btcec.PrivKeyFromBytes(btcec.S256(), tt.secret.Bytes())
+			want, err := ref.Sign(tt.z)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.r.Cmp(want.R) != 0 || got.s.Cmp(want.S) != 0 {
+				t.Errorf("PrivateKey.SignHash() = r:%x s:%x, btcec = r:%x s:%x", got.r, got.s, want.R, want.S)
+			}
+			if string(got.Der()) != string(want.Serialize()) {
+				t.Errorf("Signature.Der() = %x, btcec = %x", got.Der(), want.Serialize())
+			}
+		})
+	}
+}
+
+func TestNewPrivateKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  *big.Int
+		wantErr bool
+	}{
+		{name: "OK", secret: big.NewInt(1)},
+		{name: "NG if zero", secret: big.NewInt(0), wantErr: true},
+		{name: "NG if n", secret: genN(), wantErr: true},
+		{name: "NG if negative", secret: big.NewInt(-1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPrivateKey(tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("NewPrivateKey() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestPrivateKey_SignHashWithEntropy(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(1485))
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := chainhash.DoubleHashB([]byte("extra entropy"))
+	plain, err := p.SignHash(z)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if none, err := p.SignHashWithEntropy(z, nil); err != nil || none.r.Cmp(plain.r) != 0 || none.s.Cmp(plain.s) != 0 {
+		t.Errorf("PrivateKey.SignHashWithEntropy(nil) differs from SignHash()")
+	}
+	seen := map[string]bool{plain.r.String(): true}
+	for i := byte(1); i <= 3; i++ {
+		extra := make([]byte, 32)
+		extra[0] = i
+		sig, err := p.SignHashWithEntropy(z, extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[sig.r.String()] {
+			t.Errorf("PrivateKey.SignHashWithEntropy(%x) reused a nonce", extra)
+		}
+		seen[sig.r.String()] = true
+		if ok, _ := p.p.Verify(new(big.Int).SetBytes(z), *sig); !ok {
+			t.Errorf("PrivateKey.SignHashWithEntropy(%x) does not verify", extra)
+		}
+		if sig.s.Cmp(new(big.Int).Rsh(genN(), 1)) > 0 {
+			t.Errorf("PrivateKey.SignHashWithEntropy(%x) s is not low", extra)
+		}
+	}
+	if _, err := p.SignHashWithEntropy(z, make([]byte, 16)); err == nil {
+		t.Error("PrivateKey.SignHashWithEntropy() accepted 16 bytes of extra data")
+	}
+}
+
+func TestPrivateKey_SignHashLowR(t *testing.T) {
+	p, err := NewPrivateKey(mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 16; i++ {
+		z := chainhash.DoubleHashB([]byte{byte(i)})
+		sig, err := p.SignHashLowR(z)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sig.r.BitLen() > 255 {
+			t.Errorf("PrivateKey.SignHashLowR() r = %x is not low", sig.r)
+		}
+		if len(sig.Der()) > 70 {
+			t.Errorf("PrivateKey.SignHashLowR() DER is %d bytes", len(sig.Der()))
+		}
+		if ok, _ := p.p.Verify(new(big.Int).SetBytes(z), *sig); !ok {
+			t.Errorf("PrivateKey.SignHashLowR() does not verify")
+		}
+	}
+}
+
+func TestPrivateKey_Sign_verifiesWithStdlib(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := sha256.Sum256([]byte("crypto.Signer"))
+	der, err := p.Sign(nil, hash[:], crypto.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ecdsa.VerifyASN1(p.Public().(*PublicKey), hash[:], der) {
+		t.Error("crypto/ecdsa rejected the signature of PrivateKey.Sign()")
+	}
+	sig, err := ParseDer(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := p.p.Verify(new(big.Int).SetBytes(hash[:]), *sig); !ok {
+		t.Error("s256Point.Verify rejected the signature of PrivateKey.Sign()")
+	}
+}
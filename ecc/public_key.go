@@ -0,0 +1,33 @@
+package ecc
+
+import (
+	"crypto/ecdsa"
+
+	"golang.org/x/xerrors"
+)
+
+// PublicKey is a secp256k1 public key in the form the standard library
+// uses. It is the same type as ecdsa.PublicKey, with Curve set to S256(), so
+// it can be handed to crypto/ecdsa, crypto/x509 style type switches and JWS
+// libraries as it is.
+type PublicKey = ecdsa.PublicKey
+
+// ToECDSA returns s as a public key on S256(). s must not be infinity.
+func (s *s256Point) ToECDSA() *PublicKey {
+	return &PublicKey{Curve: S256(), X: s.x.Big(), Y: s.y.Big()}
+}
+
+// PublicKeyPoint returns the secp256k1 point of pub. It fails if pub is not
+// a finite point on secp256k1.
+func PublicKeyPoint(pub *PublicKey) (*s256Point, error) {
+	if pub == nil || pub.X == nil || pub.Y == nil {
+		return nil, xerrors.New("public key is empty")
+	}
+	if pub.Curve == nil || pub.Curve.Params().Name != S256().Params().Name {
+		return nil, xerrors.New("public key is not on secp256k1")
+	}
+	if pub.X.Sign() < 0 || pub.Y.Sign() < 0 {
+		return nil, xerrors.New("public key coordinates are negative")
+	}
+	return NewS256Point(pub.X, pub.Y)
+}
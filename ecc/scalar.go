@@ -0,0 +1,258 @@
+package ecc
+
+import (
+	"math/big"
+	"math/bits"
+)
+
+// Scalar is an integer modulo the order n of the secp256k1 group, held as
+// four little-endian 64-bit limbs. Values are always fully reduced and every
+// operation except Big and SetBig runs in constant time with respect to the
+// operands. The zero value is the scalar 0.
+type Scalar struct {
+	n [4]uint64
+}
+
+// n = 2**256 - scalarNC
+var scalarN = [4]uint64{
+	0xbfd25e8cd0364141,
+	0xbaaedce6af48a03b,
+	0xfffffffffffffffe,
+	0xffffffffffffffff,
+}
+
+// 2**256 mod n, used to fold the upper half of a product into the lower half.
+var scalarNC = [3]uint64{
+	0x402da1732fc9bebf,
+	0x4551231950b75fc4,
+	0x1,
+}
+
+// (n - 1) / 2
+var scalarHalfN = [4]uint64{
+	0xdfe92f46681b20a0,
+	0x5d576e7357a4501d,
+	0xffffffffffffffff,
+	0x7fffffffffffffff,
+}
+
+// NewScalar returns v as a scalar.
+func NewScalar(v uint64) *Scalar {
+	return &Scalar{[4]uint64{v, 0, 0, 0}}
+}
+
+// SetBytes sets s to the big-endian value b reduced modulo n and reports
+// whether b was not less than n.
+func (s *Scalar) SetBytes(b *[32]byte) (overflow bool) {
+	for i := 0; i < 4; i++ {
+		s.n[i] = uint64(b[31-8*i]) | uint64(b[30-8*i])<<8 | uint64(b[29-8*i])<<16 |
+			uint64(b[28-8*i])<<24 | uint64(b[27-8*i])<<32 | uint64(b[26-8*i])<<40 |
+			uint64(b[25-8*i])<<48 | uint64(b[24-8*i])<<56
+	}
+	return s.reduce(0) == 1
+}
+
+// SetByteSlice is SetBytes for a slice of any length. Slices longer than
+// 32 bytes are reduced modulo n as a whole, not truncated.
+func (s *Scalar) SetByteSlice(b []byte) (overflow bool) {
+	if len(b) > 32 {
+		return s.SetBig(new(big.Int).SetBytes(b))
+	}
+	var buf [32]byte
+	copy(buf[32-len(b):], b)
+	return s.SetBytes(&buf)
+}
+
+// SetBig sets s to number modulo n and reports whether number was outside
+// the range [0, n).
+func (s *Scalar) SetBig(number *big.Int) (overflow bool) {
+	var b [32]byte
+	if number.Sign() < 0 || number.BitLen() > 256 {
+		new(big.Int).Mod(number, genN()).FillBytes(b[:])
+		s.SetBytes(&b)
+		return true
+	}
+	number.FillBytes(b[:])
+	return s.SetBytes(&b)
+}
+
+// Bytes returns the 32-byte big-endian encoding of s.
+func (s *Scalar) Bytes() [32]byte {
+	var b [32]byte
+	for i := 0; i < 4; i++ {
+		for j := 0; j < 8; j++ {
+			b[31-8*i-j] = byte(s.n[i] >> (8 * j))
+		}
+	}
+	return b
+}
+
+// Big returns s as a new big.Int.
+func (s *Scalar) Big() *big.Int {
+	b := s.Bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+func (s Scalar) String() string {
+	b := s.Bytes()
+	return new(big.Int).SetBytes(b[:]).Text(16)
+}
+
+// reduce subtracts n once if carry*2**256 + s >= n and returns 1 when it
+// did so. The value must be less than 2n.
+func (s *Scalar) reduce(carry uint64) uint64 {
+	var t [4]uint64
+	var borrow uint64
+	t[0], borrow = bits.Sub64(s.n[0], scalarN[0], 0)
+	t[1], borrow = bits.Sub64(s.n[1], scalarN[1], borrow)
+	t[2], borrow = bits.Sub64(s.n[2], scalarN[2], borrow)
+	t[3], borrow = bits.Sub64(s.n[3], scalarN[3], borrow)
+	cond := carry | (borrow ^ 1)
+	s.selectFrom(&t, &s.n, cond)
+	return cond
+}
+
+// selectFrom sets s to a when cond is 1 and to b when cond is 0.
+func (s *Scalar) selectFrom(a, b *[4]uint64, cond uint64) {
+	mask := -cond
+	s.n[0] = (a[0] & mask) | (b[0] &^ mask)
+	s.n[1] = (a[1] & mask) | (b[1] &^ mask)
+	s.n[2] = (a[2] & mask) | (b[2] &^ mask)
+	s.n[3] = (a[3] & mask) | (b[3] &^ mask)
+}
+
+// Set sets s to x.
+func (s *Scalar) Set(x *Scalar) *Scalar {
+	*s = *x
+	return s
+}
+
+// Equal reports in constant time whether s and other hold the same value.
+func (s *Scalar) Equal(other *Scalar) bool {
+	d := (s.n[0] ^ other.n[0]) | (s.n[1] ^ other.n[1]) | (s.n[2] ^ other.n[2]) | (s.n[3] ^ other.n[3])
+	return ctEqual64(d, 0) == 1
+}
+
+// IsZero reports whether s is zero.
+func (s *Scalar) IsZero() bool {
+	return ctEqual64(s.n[0]|s.n[1]|s.n[2]|s.n[3], 0) == 1
+}
+
+// IsOdd reports whether s is odd.
+func (s *Scalar) IsOdd() bool {
+	return s.n[0]&1 == 1
+}
+
+// IsHigh reports whether s is greater than (n - 1) / 2, which is when a
+// signature s value has to be negated to be low-S.
+func (s *Scalar) IsHigh() bool {
+	var borrow uint64
+	_, borrow = bits.Sub64(scalarHalfN[0], s.n[0], 0)
+	_, borrow = bits.Sub64(scalarHalfN[1], s.n[1], borrow)
+	_, borrow = bits.Sub64(scalarHalfN[2], s.n[2], borrow)
+	_, borrow = bits.Sub64(scalarHalfN[3], s.n[3], borrow)
+	return borrow == 1
+}
+
+// Add sets s = x + y mod n.
+func (s *Scalar) Add(x, y *Scalar) *Scalar {
+	var carry uint64
+	s.n[0], carry = bits.Add64(x.n[0], y.n[0], 0)
+	s.n[1], carry = bits.Add64(x.n[1], y.n[1], carry)
+	s.n[2], carry = bits.Add64(x.n[2], y.n[2], carry)
+	s.n[3], carry = bits.Add64(x.n[3], y.n[3], carry)
+	s.reduce(carry)
+	return s
+}
+
+// Sub sets s = x - y mod n.
+func (s *Scalar) Sub(x, y *Scalar) *Scalar {
+	var neg Scalar
+	return s.Add(x, neg.Negate(y))
+}
+
+// Negate sets s = -x mod n.
+func (s *Scalar) Negate(x *Scalar) *Scalar {
+	var d [4]uint64
+	var borrow uint64
+	d[0], borrow = bits.Sub64(scalarN[0], x.n[0], 0)
+	d[1], borrow = bits.Sub64(scalarN[1], x.n[1], borrow)
+	d[2], borrow = bits.Sub64(scalarN[2], x.n[2], borrow)
+	d[3], _ = bits.Sub64(scalarN[3], x.n[3], borrow)
+	// -0 is 0, not n.
+	var zero [4]uint64
+	s.selectFrom(&zero, &d, ctEqual64(x.n[0]|x.n[1]|x.n[2]|x.n[3], 0))
+	return s
+}
+
+// Mul sets s = x * y mod n.
+func (s *Scalar) Mul(x, y *Scalar) *Scalar {
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 4; j++ {
+			c, t[i+j] = madd64(x.n[i], y.n[j], t[i+j], c)
+		}
+		t[i+4] = c
+	}
+	// each fold replaces the upper half t[4..7] by its product with
+	// 2**256 mod n. The value shrinks to under 2**385, 2**259, 2**256 + 2**132
+	// and finally 2**256, whatever the input.
+	for i := 0; i < 4; i++ {
+		scalarFold(&t)
+	}
+	copy(s.n[:], t[:4])
+	s.reduce(0)
+	return s
+}
+
+// scalarFold sets t = t[0..3] + t[4..7] * (2**256 mod n), which is congruent
+// to t modulo n.
+func scalarFold(t *[8]uint64) {
+	var r [8]uint64
+	copy(r[:4], t[:4])
+	for i := 0; i < 4; i++ {
+		var c uint64
+		for j := 0; j < 3; j++ {
+			c, r[i+j] = madd64(t[4+i], scalarNC[j], r[i+j], c)
+		}
+		for k := i + 3; k < 8; k++ {
+			r[k], c = bits.Add64(r[k], c, 0)
+		}
+	}
+	*t = r
+}
+
+// Square sets s = x * x mod n.
+func (s *Scalar) Square(x *Scalar) *Scalar {
+	return s.Mul(x, x)
+}
+
+// Inverse sets s = x**-1 mod n using Fermat's little theorem, and s = 0
+// when x is 0. The exponent n - 2 is public, so the sequence of operations
+// does not depend on x.
+func (s *Scalar) Inverse(x *Scalar) *Scalar {
+	exp := scalarN
+	exp[0] -= 2
+	result := *NewScalar(1)
+	base := *x
+	for i := 255; i >= 0; i-- {
+		result.Square(&result)
+		if (exp[i/64]>>(uint(i)%64))&1 == 1 {
+			result.Mul(&result, &base)
+		}
+	}
+	*s = result
+	return s
+}
+
+// inverseVar sets s = x**-1 mod n like Inverse, but in variable time. It is
+// only meant for public values such as signatures being verified.
+func (s *Scalar) inverseVar(x *Scalar) *Scalar {
+	if x.IsZero() {
+		*s = Scalar{}
+		return s
+	}
+	s.SetBig(new(big.Int).ModInverse(x.Big(), genN()))
+	return s
+}
@@ -0,0 +1,240 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func scalarTestValues() []*big.Int {
+	n := genN()
+	values := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		new(big.Int).Sub(n, big.NewInt(1)),
+		new(big.Int).Sub(n, big.NewInt(2)),
+		new(big.Int).Rsh(n, 1),
+		new(big.Int).Add(new(big.Int).Rsh(n, 1), big.NewInt(1)),
+		new(big.Int).Lsh(big.NewInt(1), 255),
+		new(big.Int).Lsh(big.NewInt(1), 128),
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		values = append(values, new(big.Int).Rand(r, n))
+	}
+	return values
+}
+
+func mustScalar(t *testing.T, v *big.Int) *Scalar {
+	t.Helper()
+	s := new(Scalar)
+	if s.SetBig(v) {
+		t.Fatalf("Scalar.SetBig(%x) overflowed", v)
+	}
+	return s
+}
+
+func TestScalar_SetBytes(t *testing.T) {
+	n := genN()
+	tests := []struct {
+		name         string
+		number       *big.Int
+		want         *big.Int
+		wantOverflow bool
+	}{
+		{
+			name:   "Ok if less than n",
+			number: big.NewInt(5),
+			want:   big.NewInt(5),
+		},
+		{
+			name:   "Ok if n - 1",
+			number: new(big.Int).Sub(n, big.NewInt(1)),
+			want:   new(big.Int).Sub(n, big.NewInt(1)),
+		},
+		{
+			name:         "Overflow if equal to n",
+			number:       n,
+			want:         big.NewInt(0),
+			wantOverflow: true,
+		},
+		{
+			name:         "Overflow if larger than n",
+			number:       new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+			want:         new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), new(big.Int).Add(n, big.NewInt(1))),
+			wantOverflow: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b [32]byte
+			tt.number.FillBytes(b[:])
+			s := new(Scalar)
+			if got := s.SetBytes(&b); got != tt.wantOverflow {
+				t.Errorf("Scalar.SetBytes() overflow = %v, want %v", got, tt.wantOverflow)
+			}
+			if s.Big().Cmp(tt.want) != 0 {
+				t.Errorf("Scalar.SetBytes() = %x, want %x", s.Big(), tt.want)
+			}
+			if got := s.Bytes(); tt.number.Cmp(n) < 0 && got != b {
+				t.Errorf("Scalar.Bytes() = %x, want %x", got, b)
+			}
+		})
+	}
+}
+
+func TestScalar_SetByteSlice(t *testing.T) {
+	n := genN()
+	tests := []struct {
+		name         string
+		in           []byte
+		want         *big.Int
+		wantOverflow bool
+	}{
+		{
+			name: "Ok if short",
+			in:   []byte{0x01, 0x02},
+			want: big.NewInt(0x0102),
+		},
+		{
+			name: "Ok if 33 bytes with a leading zero",
+			in:   append([]byte{0x00}, new(big.Int).Sub(n, big.NewInt(1)).FillBytes(make([]byte, 32))...),
+			want: new(big.Int).Sub(n, big.NewInt(1)),
+		},
+		{
+			name:         "Reduced as a whole if 33 bytes",
+			in:           append([]byte{0x01}, make([]byte, 32)...),
+			want:         new(big.Int).Mod(new(big.Int).Lsh(big.NewInt(1), 256), n),
+			wantOverflow: true,
+		},
+		{
+			name:         "Reduced as a whole if 33 bytes ending in a small value",
+			in:           append([]byte{0xff}, big.NewInt(7).FillBytes(make([]byte, 32))...),
+			want:         new(big.Int).Mod(new(big.Int).SetBytes(append([]byte{0xff}, big.NewInt(7).FillBytes(make([]byte, 32))...)), n),
+			wantOverflow: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := new(Scalar)
+			if got := s.SetByteSlice(tt.in); got != tt.wantOverflow {
+				t.Errorf("Scalar.SetByteSlice() overflow = %v, want %v", got, tt.wantOverflow)
+			}
+			if s.Big().Cmp(tt.want) != 0 {
+				t.Errorf("Scalar.SetByteSlice() = %x, want %x", s.Big(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScalar_SetBig(t *testing.T) {
+	n := genN()
+	tests := []struct {
+		name         string
+		number       *big.Int
+		want         *big.Int
+		wantOverflow bool
+	}{
+		{name: "Ok if in range", number: big.NewInt(42), want: big.NewInt(42)},
+		{name: "Overflow if negative", number: big.NewInt(-1), want: new(big.Int).Sub(n, big.NewInt(1)), wantOverflow: true},
+		{name: "Overflow if wider than 256 bits", number: new(big.Int).Add(new(big.Int).Lsh(n, 8), big.NewInt(3)), want: big.NewInt(3), wantOverflow: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := new(big.Int).Set(tt.number)
+			s := new(Scalar)
+			if got := s.SetBig(tt.number); got != tt.wantOverflow {
+				t.Errorf("Scalar.SetBig() overflow = %v, want %v", got, tt.wantOverflow)
+			}
+			if s.Big().Cmp(tt.want) != 0 {
+				t.Errorf("Scalar.SetBig() = %x, want %x", s.Big(), tt.want)
+			}
+			if tt.number.Cmp(before) != 0 {
+				t.Errorf("Scalar.SetBig() modified its argument")
+			}
+		})
+	}
+}
+
+func TestScalar_arithmetic(t *testing.T) {
+	n := genN()
+	values := scalarTestValues()
+	for i, a := range values {
+		b := values[(i*7+3)%len(values)]
+		sa, sb := mustScalar(t, a), mustScalar(t, b)
+
+		want := new(big.Int).Add(a, b)
+		if got := new(Scalar).Add(sa, sb); got.Big().Cmp(want.Mod(want, n)) != 0 {
+			t.Errorf("Scalar.Add(%x, %x) = %x, want %x", a, b, got.Big(), want)
+		}
+		want = new(big.Int).Sub(a, b)
+		if got := new(Scalar).Sub(sa, sb); got.Big().Cmp(want.Mod(want, n)) != 0 {
+			t.Errorf("Scalar.Sub(%x, %x) = %x, want %x", a, b, got.Big(), want)
+		}
+		want = new(big.Int).Mul(a, b)
+		if got := new(Scalar).Mul(sa, sb); got.Big().Cmp(want.Mod(want, n)) != 0 {
+			t.Errorf("Scalar.Mul(%x, %x) = %x, want %x", a, b, got.Big(), want)
+		}
+		want = new(big.Int).Neg(a)
+		if got := new(Scalar).Negate(sa); got.Big().Cmp(want.Mod(want, n)) != 0 {
+			t.Errorf("Scalar.Negate(%x) = %x, want %x", a, got.Big(), want)
+		}
+		want = new(big.Int).ModInverse(a, n)
+		if want == nil {
+			want = new(big.Int)
+		}
+		if got := new(Scalar).Inverse(sa); got.Big().Cmp(want) != 0 {
+			t.Errorf("Scalar.Inverse(%x) = %x, want %x", a, got.Big(), want)
+		}
+		if got := new(Scalar).inverseVar(sa); got.Big().Cmp(want) != 0 {
+			t.Errorf("Scalar.inverseVar(%x) = %x, want %x", a, got.Big(), want)
+		}
+		if got, want := sa.IsHigh(), a.Cmp(new(big.Int).Rsh(n, 1)) > 0; got != want {
+			t.Errorf("Scalar.IsHigh(%x) = %v, want %v", a, got, want)
+		}
+		if got, want := sa.Equal(sb), a.Cmp(b) == 0; got != want {
+			t.Errorf("Scalar.Equal(%x, %x) = %v, want %v", a, b, got, want)
+		}
+	}
+}
+
+func TestScalar_aliasing(t *testing.T) {
+	a := mustScalar(t, mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"))
+	want := new(Scalar).Mul(a, a)
+	if got := new(Scalar).Set(a); !got.Mul(got, got).Equal(want) {
+		t.Errorf("Scalar.Mul(s, s) into s = %v, want %v", got, want)
+	}
+	want = new(Scalar).Inverse(a)
+	if got := new(Scalar).Set(a); !got.Inverse(got).Equal(want) {
+		t.Errorf("Scalar.Inverse(s) into s = %v, want %v", got, want)
+	}
+}
+
+func TestS256Point_SRMul_doesNotModifyCoefficient(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	k := new(big.Int).Add(genN(), big.NewInt(5))
+	g.SRMul(k)
+	if k.Cmp(new(big.Int).Add(genN(), big.NewInt(5))) != 0 {
+		t.Errorf("SRMul() modified its coefficient to %x", k)
+	}
+}
+
+func BenchmarkScalar_Mul(b *testing.B) {
+	x := new(Scalar)
+	x.SetBig(mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"))
+	for i := 0; i < b.N; i++ {
+		x.Mul(x, x)
+	}
+}
+
+func BenchmarkScalar_Inverse(b *testing.B) {
+	x := new(Scalar)
+	x.SetBig(mustGetFromHex("0x3fac8b1d5e6a03bc2d01d4ad1e9e2f0c5b9f7d1e2a3b4c5d6e7f8091a2b3c4d5"))
+	for i := 0; i < b.N; i++ {
+		x.Inverse(x)
+	}
+}
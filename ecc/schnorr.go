@@ -0,0 +1,216 @@
+package ecc
+
+import (
+	"crypto/sha256"
+
+	"golang.org/x/xerrors"
+)
+
+// BIP340 tags.
+const (
+	tagBIP340Aux       = "BIP0340/aux"
+	tagBIP340Nonce     = "BIP0340/nonce"
+	tagBIP340Challenge = "BIP0340/challenge"
+)
+
+// schnorrSigLen is the size of a BIP340 signature: the x coordinate of R
+// and s as 32 bytes each.
+const schnorrSigLen = 64
+
+// taggedHash returns SHA256(SHA256(tag) || SHA256(tag) || msgs...), the
+// domain separated hash of BIP340.
+func taggedHash(tag string, msgs ...[]byte) [32]byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, m := range msgs {
+		h.Write(m)
+	}
+	var out [32]byte
+	copy(out[:], h.Sum(nil))
+	return out
+}
+
+// SchnorrSignature is a BIP340 signature. R is implicitly the point with x
+// coordinate r and an even y.
+// https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+type SchnorrSignature struct {
+	r s256FieldElement
+	s Scalar
+}
+
+// Bytes returns the 64-byte encoding r || s.
+func (sig *SchnorrSignature) Bytes() []byte {
+	r := sig.r.Bytes()
+	s := sig.s.Bytes()
+	return append(r[:], s[:]...)
+}
+
+// ParseSchnorrSignature decodes a 64-byte BIP340 signature. r must be below
+// the field prime and s below the group order.
+func ParseSchnorrSignature(b []byte) (*SchnorrSignature, error) {
+	if len(b) != schnorrSigLen {
+		return nil, xerrors.Errorf("schnorr signature must be %d bytes, got %d: %w", schnorrSigLen, len(b), ErrInvalidSchnorrSigLength)
+	}
+	sig := &SchnorrSignature{}
+	var rb, sb [32]byte
+	copy(rb[:], b[:32])
+	copy(sb[:], b[32:])
+	if sig.r.SetBytes(&rb) {
+		return nil, xerrors.Errorf("r is not below p: %w", ErrSchnorrSigRange)
+	}
+	if sig.s.SetBytes(&sb) {
+		return nil, xerrors.Errorf("s is not below n: %w", ErrSchnorrSigRange)
+	}
+	return sig, nil
+}
+
+// XOnly returns the 32-byte x-only encoding of s used by BIP340 and
+// taproot. It drops the parity of y, so s and -s encode the same. The
+// point at infinity has no x-only encoding and gives nil.
+func (s *s256Point) XOnly() []byte {
+	if s.IsInfinity() {
+		return nil
+	}
+	x := s.x.Bytes()
+	return x[:]
+}
+
+// evenY returns s if its y is even and -s otherwise, the point that the
+// x-only encoding of s stands for.
+func (s *s256Point) evenY() *s256Point {
+	if s.IsInfinity() || !s.y.IsOdd() {
+		return s
+	}
+	return s.Negate()
+}
+
+// Negate returns -s as a new point.
+func (s *s256Point) Negate() *s256Point {
+	if s.IsInfinity() {
+		return S256Infinity()
+	}
+	return &s256Point{s.x, new(s256FieldElement).Neg(s.y), genN()}
+}
+
+// ParseXOnly decodes a 32-byte x-only public key into the point with that x
+// coordinate and an even y (lift_x of BIP340).
+func ParseXOnly(b []byte) (*s256Point, error) {
+	if len(b) != 32 {
+		return nil, xerrors.Errorf("x-only public key must be 32 bytes, got %d: %w", len(b), ErrInvalidPubKeyLength)
+	}
+	var xb [32]byte
+	copy(xb[:], b)
+	x := new(s256FieldElement)
+	if x.SetBytes(&xb) {
+		return nil, xerrors.Errorf("x is larger than prime: %w", ErrPubKeyNotOnCurve)
+	}
+	return decompressS256(x, false)
+}
+
+// SignSchnorr returns the BIP340 signature of msg, which may have any
+// length. auxRand is 32 bytes of fresh randomness mixed into the nonce to
+// protect against side channels; nil stands for 32 zero bytes, which gives
+// deterministic signatures that are still secure.
+func (p *PrivateKey) SignSchnorr(msg, auxRand []byte) (*SchnorrSignature, error) {
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+	}
+	if len(auxRand) != 32 {
+		return nil, xerrors.Errorf("aux randomness must be 32 bytes, got %d", len(auxRand))
+	}
+	// d = secret, negated if needed so that d*G has an even y.
+	var d Scalar
+	d.Set(&p.secret)
+	if p.p.y.IsOdd() {
+		d.Negate(&d)
+	}
+	pub := p.p.XOnly()
+
+	// t = bytes(d) xor hash_aux(a)
+	t := d.Bytes()
+	aux := taggedHash(tagBIP340Aux, auxRand)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+	rand := taggedHash(tagBIP340Nonce, t[:], pub, msg)
+	var k Scalar
+	k.SetBytes(&rand)
+	if k.IsZero() {
+		return nil, xerrors.New("nonce is zero")
+	}
+	return p.signSchnorr(msg, &d, &k, ScalarBaseMul(&k))
+}
+
+// SignSchnorrWithNonce returns the BIP340 signature of msg with the nonce
+// R of the given key instead of a derived one. It is for DLC oracles, which
+// announce R before they sign. Signing two messages with one nonce reveals
+// the private key, so every nonce must be used once only.
+func (p *PrivateKey) SignSchnorrWithNonce(msg []byte, nonce *PrivateKey) (*SchnorrSignature, error) {
+	var d, k Scalar
+	d.Set(&p.secret)
+	if p.p.y.IsOdd() {
+		d.Negate(&d)
+	}
+	k.Set(&nonce.secret)
+	return p.signSchnorr(msg, &d, &k, nonce.p)
+}
+
+// signSchnorr returns the signature with the even-y secret d and the nonce
+// k with R = k*G. k is negated in place if R has an odd y.
+func (p *PrivateKey) signSchnorr(msg []byte, d, k *Scalar, R *s256Point) (*SchnorrSignature, error) {
+	if R.y.IsOdd() {
+		k.Negate(k)
+	}
+	sig := &SchnorrSignature{r: *R.x}
+	e := schnorrChallenge(R.XOnly(), p.p.XOnly(), msg)
+	// s = k + e*d
+	sig.s.Mul(&e, d).Add(&sig.s, k)
+
+	// BIP340 recommends checking the result against faults.
+	if ok, err := p.p.VerifySchnorr(msg, sig); err != nil || !ok {
+		return nil, xerrors.New("created an invalid schnorr signature")
+	}
+	return sig, nil
+}
+
+// SchnorrSignaturePoint returns s*G for the BIP340 signature (R.x, s) of
+// msg by pub with the nonce R, that is R + e*P with both points taken with
+// an even y. Anyone can compute it before the signature exists, so it can
+// serve as the adaptor point of a contract that pays out once pub signs
+// msg.
+func SchnorrSignaturePoint(pub, nonce *s256Point, msg []byte) (*s256Point, error) {
+	if pub.IsInfinity() || nonce.IsInfinity() {
+		return nil, xerrors.Errorf("signature point: %w", ErrPubKeyInfinity)
+	}
+	e := schnorrChallenge(nonce.XOnly(), pub.XOnly(), msg)
+	return pub.evenY().ScalarMul(&e).Add(nonce.evenY()), nil
+}
+
+// schnorrChallenge returns e = hash_challenge(r || P || msg) mod n.
+func schnorrChallenge(r, pub, msg []byte) Scalar {
+	h := taggedHash(tagBIP340Challenge, r, pub, msg)
+	var e Scalar
+	e.SetBytes(&h)
+	return e
+}
+
+// VerifySchnorr reports whether sig is a valid BIP340 signature of msg by
+// the x-only public key of s, that is whether s*G - e*P is the point with x
+// coordinate r and an even y, where P is s or -s whichever has an even y.
+func (s *s256Point) VerifySchnorr(msg []byte, sig *SchnorrSignature) (bool, error) {
+	if s.IsInfinity() {
+		return false, xerrors.New("public key is infinity")
+	}
+	pub := s.XOnly()
+	P := s.evenY()
+	r := sig.r.Bytes()
+	e := schnorrChallenge(r[:], pub, msg)
+	// R = s*G + (-e)*P
+	R := DoubleScalarMul(sig.s.Big(), new(Scalar).Negate(&e).Big(), P)
+	if R.IsInfinity() || R.y.IsOdd() {
+		return false, nil
+	}
+	return R.x.Equal(&sig.r), nil
+}
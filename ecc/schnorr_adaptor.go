@@ -0,0 +1,170 @@
+package ecc
+
+import "golang.org/x/xerrors"
+
+// tagSchnorrAdaptorNonce domain separates the Schnorr adaptor nonce. Like
+// FROST there is no BIP for it, so the tag is our own. The nonce never
+// leaves the signer, so other implementations still verify and complete
+// these pre-signatures: they only depend on the encoding and the equation
+// of VerifySchnorrAdaptor.
+const tagSchnorrAdaptorNonce = "SchnorrAdaptor/nonce"
+
+const schnorrAdaptorSigLen = 33 + 32
+
+// SchnorrAdaptorSignature is a BIP340 pre-signature for an adaptor point T.
+// Adding the discrete log t of T to it gives a valid signature, and anyone
+// who sees both learns t, which is what atomic swaps and DLCs build on.
+type SchnorrAdaptorSignature struct {
+	// r is R = k*G + T. Its y may be odd, in which case k was negated.
+	r *s256Point
+	s Scalar
+}
+
+// Bytes returns the 65-byte encoding of sig: R compressed, then s'.
+func (sig *SchnorrAdaptorSignature) Bytes() []byte {
+	s := sig.s.Bytes()
+	return append(sig.r.Sec(true), s[:]...)
+}
+
+// ParseSchnorrAdaptorSignature decodes the 65-byte encoding of Bytes.
+func ParseSchnorrAdaptorSignature(b []byte) (*SchnorrAdaptorSignature, error) {
+	if len(b) != schnorrAdaptorSigLen {
+		return nil, xerrors.Errorf("got %d bytes, want %d: %w", len(b), schnorrAdaptorSigLen, ErrInvalidAdaptorSigLength)
+	}
+	r, err := ParseSec(b[:33])
+	if err != nil {
+		return nil, xerrors.Errorf("R: %w", err)
+	}
+	sig := &SchnorrAdaptorSignature{r: r}
+	var sb [32]byte
+	copy(sb[:], b[33:])
+	if sig.s.SetBytes(&sb) {
+		return nil, xerrors.Errorf("s is not below n: %w", ErrSchnorrSigRange)
+	}
+	return sig, nil
+}
+
+// adaptorNonce derives a nonce like BIP340 does from the secret masked with
+// auxRand, the public key, the adaptor point and the message. Committing to
+// the adaptor point keeps a key from reusing a nonce for two adaptors of the
+// same message, which would reveal the secret.
+func adaptorNonce(tag string, secret *Scalar, auxRand, pub, adaptor, msg []byte) (Scalar, error) {
+	var k Scalar
+	if auxRand == nil {
+		auxRand = make([]byte, 32)
+	}
+	if len(auxRand) != 32 {
+		return k, xerrors.Errorf("aux randomness must be 32 bytes, got %d", len(auxRand))
+	}
+	t := secret.Bytes()
+	aux := taggedHash(tagBIP340Aux, auxRand)
+	for i := range t {
+		t[i] ^= aux[i]
+	}
+	rand := taggedHash(tag, t[:], pub, adaptor, msg)
+	k.SetBytes(&rand)
+	if k.IsZero() {
+		return k, xerrors.New("nonce is zero")
+	}
+	return k, nil
+}
+
+// validAdaptor rejects adaptor points that cannot hide a secret.
+func validAdaptor(adaptor *s256Point) error {
+	if adaptor == nil || adaptor.IsInfinity() {
+		return xerrors.Errorf("adaptor point: %w", ErrPubKeyInfinity)
+	}
+	return nil
+}
+
+// SignSchnorrAdaptor returns a pre-signature of msg for the adaptor point
+// T: s' = k + e*d with R = k*G + T and e = hash_challenge(R.x || P || msg),
+// k negated when R has an odd y. It is not a valid signature, but Complete
+// turns it into one given the t with T = t*G. auxRand is as in SignSchnorr.
+func (p *PrivateKey) SignSchnorrAdaptor(msg []byte, adaptor *s256Point, auxRand []byte) (*SchnorrAdaptorSignature, error) {
+	if err := validAdaptor(adaptor); err != nil {
+		return nil, err
+	}
+	var d Scalar
+	d.Set(&p.secret)
+	if p.p.y.IsOdd() {
+		d.Negate(&d)
+	}
+	pub := p.p.XOnly()
+	k, err := adaptorNonce(tagSchnorrAdaptorNonce, &d, auxRand, pub, adaptor.Sec(true), msg)
+	if err != nil {
+		return nil, err
+	}
+	R := ScalarBaseMul(&k).Add(adaptor)
+	if R.IsInfinity() {
+		return nil, xerrors.New("nonce cancels the adaptor point")
+	}
+	if R.y.IsOdd() {
+		k.Negate(&k)
+	}
+	sig := &SchnorrAdaptorSignature{r: R}
+	e := schnorrChallenge(R.XOnly(), pub, msg)
+	// s' = k + e*d
+	sig.s.Mul(&e, &d).Add(&sig.s, &k)
+
+	if ok, err := p.p.VerifySchnorrAdaptor(msg, adaptor, sig); err != nil || !ok {
+		return nil, xerrors.New("created an invalid adaptor signature")
+	}
+	return sig, nil
+}
+
+// VerifySchnorrAdaptor reports whether sig is a pre-signature of msg by the
+// x-only public key of s for the adaptor point T, that is whether
+// s'*G - e*P is R - T, or T - R if R has an odd y. If it is, completing sig
+// with the discrete log of T gives a valid BIP340 signature.
+func (s *s256Point) VerifySchnorrAdaptor(msg []byte, adaptor *s256Point, sig *SchnorrAdaptorSignature) (bool, error) {
+	if s.IsInfinity() {
+		return false, xerrors.New("public key is infinity")
+	}
+	if err := validAdaptor(adaptor); err != nil {
+		return false, err
+	}
+	e := schnorrChallenge(sig.r.XOnly(), s.XOnly(), msg)
+	// k*G = s'*G + (-e)*P
+	kG := DoubleScalarMul(sig.s.Big(), new(Scalar).Negate(&e).Big(), s.evenY())
+	want := sig.r.Add(adaptor.Negate())
+	if sig.r.y.IsOdd() {
+		want = want.Negate()
+	}
+	return kG.Eq(want), nil
+}
+
+// Complete returns the BIP340 signature (R.x, s' + t), or (R.x, s' - t)
+// if R has an odd y, where t is the discrete log of the adaptor point. It
+// does not check t; a wrong t gives an invalid signature.
+func (sig *SchnorrAdaptorSignature) Complete(t *Scalar) *SchnorrSignature {
+	out := &SchnorrSignature{r: *sig.r.x}
+	if sig.r.y.IsOdd() {
+		out.s.Sub(&sig.s, t)
+	} else {
+		out.s.Add(&sig.s, t)
+	}
+	return out
+}
+
+// Extract returns the discrete log t of the adaptor point from sig and the
+// signature that completed it. It returns ErrAdaptorMismatch if final was
+// not made from sig for that adaptor point.
+func (sig *SchnorrAdaptorSignature) Extract(final *SchnorrSignature, adaptor *s256Point) (*Scalar, error) {
+	if err := validAdaptor(adaptor); err != nil {
+		return nil, err
+	}
+	if !final.r.Equal(sig.r.x) {
+		return nil, xerrors.Errorf("R differs: %w", ErrAdaptorMismatch)
+	}
+	t := new(Scalar)
+	if sig.r.y.IsOdd() {
+		t.Sub(&sig.s, &final.s)
+	} else {
+		t.Sub(&final.s, &sig.s)
+	}
+	if !ScalarBaseMul(t).Eq(adaptor) {
+		return nil, xerrors.Errorf("t*G is not the adaptor point: %w", ErrAdaptorMismatch)
+	}
+	return t, nil
+}
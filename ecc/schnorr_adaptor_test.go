@@ -0,0 +1,147 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestSchnorrAdaptor(t *testing.T) {
+	// 1 has an even y and 3 an odd one, for both the key and the adaptor.
+	keys := []string{
+		"0000000000000000000000000000000000000000000000000000000000000001",
+		"0000000000000000000000000000000000000000000000000000000000000003",
+		"c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9",
+	}
+	var parities [2]bool
+	for _, key := range keys {
+		p := mustPrivateKeyFromHex(t, key)
+		for _, secret := range keys {
+			adaptorSecret := mustPrivateKeyFromHex(t, secret)
+			T := adaptorSecret.p
+			// several messages, so that R has an odd y in some of them.
+			for m := 0; m < 4; m++ {
+				msg := []byte(fmt.Sprintf("swap %d", m))
+				pre, err := p.SignSchnorrAdaptor(msg, T, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				parities[pre.r.y.Bytes()[31]&1] = true
+				parsed, err := ParseSchnorrAdaptorSignature(pre.Bytes())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ok, err := p.p.VerifySchnorrAdaptor(msg, T, parsed); err != nil || !ok {
+					t.Fatalf("VerifySchnorrAdaptor() = %v, %v, want true", ok, err)
+				}
+				// a pre-signature is not a signature by itself.
+				if verifySchnorrBytes(p.p.XOnly(), msg, pre.Bytes()[1:]) {
+					t.Error("pre-signature verifies as a signature")
+				}
+				sig := parsed.Complete(&adaptorSecret.secret)
+				if !verifySchnorrBytes(p.p.XOnly(), msg, sig.Bytes()) {
+					t.Errorf("completed signature of %q by %s does not verify", msg, key)
+				}
+				got, err := pre.Extract(sig, T)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !got.Equal(&adaptorSecret.secret) {
+					t.Errorf("Extract() = %x, want %s", got.Bytes(), secret)
+				}
+			}
+		}
+	}
+	if !parities[0] || !parities[1] {
+		t.Error("the tests do not cover both parities of R")
+	}
+}
+
+func TestSchnorrAdaptorInvalid(t *testing.T) {
+	p := mustPrivateKeyFromHex(t, "c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9")
+	adaptorSecret := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000003")
+	T := adaptorSecret.p
+	msg := []byte("swap")
+	pre, err := p.SignSchnorrAdaptor(msg, T, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := mustPrivateKeyFromHex(t, "0000000000000000000000000000000000000000000000000000000000000002")
+
+	tests := []struct {
+		name string
+		pub  *s256Point
+		msg  []byte
+		T    *s256Point
+	}{
+		{"other key", other.p, msg, T},
+		{"other message", p.p, []byte("swap!"), T},
+		{"other adaptor", p.p, msg, other.p},
+	}
+	for _, tt := range tests {
+		if ok, err := tt.pub.VerifySchnorrAdaptor(tt.msg, tt.T, pre); err != nil || ok {
+			t.Errorf("%s: VerifySchnorrAdaptor() = %v, %v, want false", tt.name, ok, err)
+		}
+	}
+	if _, err := p.SignSchnorrAdaptor(msg, S256Infinity(), nil); !errors.Is(err, ErrPubKeyInfinity) {
+		t.Errorf("SignSchnorrAdaptor(infinity) error = %v, want %v", err, ErrPubKeyInfinity)
+	}
+
+	// a signature that does not come from pre, or the wrong adaptor point.
+	unrelated, err := p.SignSchnorr(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pre.Extract(unrelated, T); !errors.Is(err, ErrAdaptorMismatch) {
+		t.Errorf("Extract(unrelated) error = %v, want %v", err, ErrAdaptorMismatch)
+	}
+	sig := pre.Complete(&adaptorSecret.secret)
+	if _, err := pre.Extract(sig, other.p); !errors.Is(err, ErrAdaptorMismatch) {
+		t.Errorf("Extract(other adaptor) error = %v, want %v", err, ErrAdaptorMismatch)
+	}
+
+	for _, b := range [][]byte{pre.Bytes()[:64], append(pre.Bytes(), 0)} {
+		if _, err := ParseSchnorrAdaptorSignature(b); !errors.Is(err, ErrInvalidAdaptorSigLength) {
+			t.Errorf("ParseSchnorrAdaptorSignature(%d bytes) error = %v, want %v", len(b), err, ErrInvalidAdaptorSigLength)
+		}
+	}
+}
+
+func TestSchnorrAdaptorVectors(t *testing.T) {
+	// these are regression values generated by this implementation, not
+	// interoperability vectors: the nonce tag is our own. They pin the nonce
+	// and the encoding so that a change to either is noticed.
+	tests := []struct {
+		secret, adaptorSecret, aux, msg string
+		pre, sig                        string
+	}{
+		{
+			secret:        "c90fdaa22168c234c4c6628b80dc1cd129024e088a67cc74020bbea63b14e5c9",
+			adaptorSecret: "0b432b2677937381aef05bb02a66ecd012773062cf3fa2549e44f58ed2401710",
+			aux:           "0000000000000000000000000000000000000000000000000000000000000001",
+			msg:           "243f6a8885a308d313198a2e03707344a4093822299f31d0082efa98ec4e6c89",
+			pre:           "02af4fff959f14d4f967bfbb5ea508ddae8f481a41eb61ea3b639a45de2d06338fbee825ce37bf93d3e03789615c1f44adec26ccfa1d244e24e969a59d2fdc8009",
+			sig:           "af4fff959f14d4f967bfbb5ea508ddae8f481a41eb61ea3b639a45de2d06338fca2b50f4af5307558f27e5118686317dfe9dfd5cec63f07987ae9b2c021c9719",
+		},
+	}
+	for _, tt := range tests {
+		p := mustPrivateKeyFromHex(t, tt.secret)
+		y := mustPrivateKeyFromHex(t, tt.adaptorSecret)
+		msg := mustHex(t, tt.msg)
+		pre, err := p.SignSchnorrAdaptor(msg, y.p, mustHex(t, tt.aux))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(pre.Bytes()); got != tt.pre {
+			t.Errorf("SignSchnorrAdaptor() = %s, want %s", got, tt.pre)
+		}
+		sig := pre.Complete(&y.secret)
+		if got := hex.EncodeToString(sig.Bytes()); got != tt.sig {
+			t.Errorf("Complete() = %s, want %s", got, tt.sig)
+		}
+		if !verifySchnorrBytes(p.p.XOnly(), msg, mustHex(t, tt.sig)) {
+			t.Errorf("%s does not verify", tt.sig)
+		}
+	}
+}
@@ -31,44 +31,175 @@ func (s *Signature) Der() []byte {
 	return b
 }
 
-//https://github.com/btcsuite/btcd/blob/master/btcec/signature.go#L93
+// maxDerLen is the largest DER signature BIP66 allows, with r and s of 33
+// bytes each.
+const maxDerLen = 72
+
+// ParseDer decodes a DER encoded signature 0x30 len 0x02 rlen r 0x02 slen s
+// and enforces the strict encoding rules of BIP66: no trailing bytes, no
+// negative integers and no leading zero bytes unless the next byte has its
+// high bit set. Any signature it accepts is reproduced exactly by Der. It
+// never reads past der, and the returned errors wrap one of the ErrDER
+// sentinels.
+// https://github.com/bitcoin/bips/blob/master/bip-0066.mediawiki
 func ParseDer(der []byte) (*Signature, error) {
-	// 0x30
+	// 0x30 len 0x02 0x01 r 0x02 0x01 s is the shortest possible signature.
+	if len(der) < 8 {
+		return nil, xerrors.Errorf("malformed signature: %d bytes: %w", len(der), ErrDERTruncated)
+	}
+	if len(der) > maxDerLen {
+		return nil, xerrors.Errorf("malformed signature: %d bytes is too long: %w", len(der), ErrDERInvalidLength)
+	}
 	index := 0
 	if der[index] != 0x30 {
-		return nil, xerrors.New("malformed signature: no header magic")
+		return nil, xerrors.Errorf("malformed signature: no header magic: %w", ErrDERInvalidHeader)
 	}
 	index++
-	derLen := der[index]
-	if int(derLen+2) != len(der) {
-		return nil, xerrors.New("malformed signature: bad length")
+	derLen := int(der[index])
+	if derLen+2 > len(der) {
+		return nil, xerrors.Errorf("malformed signature: declares %d bytes, has %d: %w", derLen+2, len(der), ErrDERTruncated)
+	}
+	if derLen+2 < len(der) {
+		return nil, xerrors.Errorf("malformed signature: declares %d bytes, has %d: %w", derLen+2, len(der), ErrDERInvalidLength)
 	}
 	index++
-	marker := der[index]
-	if marker != 0x02 {
-		return nil, xerrors.New("malformed signature: bad signature")
+	r, index, err := parseDerInt(der, index)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed signature: r: %w", err)
+	}
+	s, index, err := parseDerInt(der, index)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed signature: s: %w", err)
+	}
+	if index != len(der) {
+		return nil, xerrors.Errorf("malformed signature: %d trailing bytes: %w", len(der)-index, ErrDERInvalidLength)
+	}
+	return NewSignature(
+		new(big.Int).SetBytes(r),
+		new(big.Int).SetBytes(s),
+	), nil
+}
+
+// parseDerInt reads the minimally encoded, non-negative INTEGER starting at
+// der[index] and returns its content bytes and the index just past it.
+func parseDerInt(der []byte, index int) ([]byte, int, error) {
+	if index+2 > len(der) {
+		return nil, 0, ErrDERTruncated
+	}
+	if der[index] != 0x02 {
+		return nil, 0, ErrDERInvalidIntMarker
 	}
 	index++
-	rlen := der[index]
+	length := int(der[index])
+	index++
+	if length == 0 {
+		return nil, 0, ErrDERZeroLength
+	}
+	if index+length > len(der) {
+		return nil, 0, ErrDERTruncated
+	}
+	b := der[index : index+length]
+	if b[0]&0x80 != 0 {
+		return nil, 0, ErrDERNegativeInt
+	}
+	if length > 1 && b[0] == 0x00 && b[1]&0x80 == 0 {
+		return nil, 0, ErrDERExcessPadding
+	}
+	return b, index + length, nil
+}
+
+// ParseDerLax decodes signatures the way Bitcoin Core's
+// ecdsa_signature_parse_der_lax does, for signatures that predate BIP66. It
+// accepts long form lengths, ignores the sequence length and anything after
+// s, and strips any number of leading zeros. As in Core, an r or s that does
+// not fit below n yields the signature (0, 0), which never verifies, rather
+// than an error.
+// https://github.com/bitcoin/bitcoin/blob/master/src/pubkey.cpp
+func ParseDerLax(der []byte) (*Signature, error) {
+	index := 0
+	if index == len(der) || der[index] != 0x30 {
+		return nil, xerrors.Errorf("malformed signature: no header magic: %w", ErrDERInvalidHeader)
+	}
 	index++
-	r := der[index : index+int(rlen)]
+	if index == len(der) {
+		return nil, xerrors.Errorf("malformed signature: no sequence length: %w", ErrDERTruncated)
+	}
+	lenByte := int(der[index])
 	index++
-	marker = der[index]
-	if marker != 0x02 {
-		return nil, xerrors.New("malformed signature: bad signature")
+	if lenByte&0x80 != 0 {
+		// the sequence length itself is not used, only skipped.
+		lenByte -= 0x80
+		if lenByte > len(der)-index {
+			return nil, xerrors.Errorf("malformed signature: sequence length: %w", ErrDERTruncated)
+		}
+		index += lenByte
+	}
+	r, index, err := parseDerIntLax(der, index)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed signature: r: %w", err)
+	}
+	s, _, err := parseDerIntLax(der, index)
+	if err != nil {
+		return nil, xerrors.Errorf("malformed signature: s: %w", err)
+	}
+	r, s = lstripZeros(r), lstripZeros(s)
+	sig := NewSignature(new(big.Int).SetBytes(r), new(big.Int).SetBytes(s))
+	if len(r) > 32 || len(s) > 32 || sig.r.Cmp(genN()) >= 0 || sig.s.Cmp(genN()) >= 0 {
+		return NewSignature(new(big.Int), new(big.Int)), nil
+	}
+	return sig, nil
+}
+
+// parseDerIntLax reads the INTEGER starting at der[index], whose length may
+// be in long form, and returns its content bytes and the index just past it.
+func parseDerIntLax(der []byte, index int) ([]byte, int, error) {
+	if index == len(der) {
+		return nil, 0, ErrDERTruncated
+	}
+	if der[index] != 0x02 {
+		return nil, 0, ErrDERInvalidIntMarker
 	}
 	index++
-	slen := der[index]
+	if index == len(der) {
+		return nil, 0, ErrDERTruncated
+	}
+	lenByte := int(der[index])
 	index++
-	s := der[index : index+int(slen)]
-	if int(len(der)) != int(6+rlen+slen) {
-		return nil, xerrors.New("malformed signature: bad length")
+	length := lenByte
+	if lenByte&0x80 != 0 {
+		lenByte -= 0x80
+		if lenByte > len(der)-index {
+			return nil, 0, ErrDERTruncated
+		}
+		for lenByte > 0 && der[index] == 0 {
+			index++
+			lenByte--
+		}
+		// Core rejects lengths of 4 bytes or more after the zeros.
+		if lenByte >= 4 {
+			return nil, 0, ErrDERInvalidLength
+		}
+		length = 0
+		for lenByte > 0 {
+			length = length<<8 + int(der[index])
+			index++
+			lenByte--
+		}
 	}
-	return NewSignature(
-		new(big.Int).SetBytes(r),
-		new(big.Int).SetBytes(s),
-	), nil
+	if length > len(der)-index {
+		return nil, 0, ErrDERTruncated
+	}
+	return der[index : index+length], index + length, nil
+}
+
+// lstripZeros returns b without its leading zero bytes.
+func lstripZeros(b []byte) []byte {
+	for len(b) > 0 && b[0] == 0x00 {
+		b = b[1:]
+	}
+	return b
 }
+
 func lstrip(bs []byte) []byte {
 	lstriped := []byte{}
 	for _, b := range bs {
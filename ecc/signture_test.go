@@ -1,9 +1,12 @@
 package ecc
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
 	"math/big"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -68,3 +71,180 @@ func TestSignature_Der_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDer_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{name: "NG if empty", in: "", wantErr: ErrDERTruncated},
+		{name: "NG if shorter than the minimum", in: "30050201010201", wantErr: ErrDERTruncated},
+		{name: "NG if header is not a sequence", in: "3106020101020101", wantErr: ErrDERInvalidHeader},
+		{name: "NG if length is beyond the buffer", in: "3007020101020101", wantErr: ErrDERTruncated},
+		{name: "NG if trailing bytes", in: "300602010102010100", wantErr: ErrDERInvalidLength},
+		{name: "NG if r marker is wrong", in: "3006030101020101", wantErr: ErrDERInvalidIntMarker},
+		{name: "NG if s marker is wrong", in: "3006020101030101", wantErr: ErrDERInvalidIntMarker},
+		{name: "NG if r is zero length", in: "300702000201010000", wantErr: ErrDERZeroLength},
+		{name: "NG if r length is beyond the buffer", in: "3006020901020101", wantErr: ErrDERTruncated},
+		{name: "NG if s length is beyond the buffer", in: "3006020101020201", wantErr: ErrDERTruncated},
+		{name: "NG if lengths leave bytes over", in: "30070201010201010000", wantErr: ErrDERInvalidLength},
+		{name: "NG if r is negative", in: "3006020181020101", wantErr: ErrDERNegativeInt},
+		{name: "NG if s is negative", in: "3006020101020181", wantErr: ErrDERNegativeInt},
+		{name: "NG if r has excess padding", in: "300702020001020101", wantErr: ErrDERExcessPadding},
+		{name: "NG if s has excess padding", in: "300702010102020001", wantErr: ErrDERExcessPadding},
+		{name: "NG if longer than 72 bytes", in: "3047022200" + strings.Repeat("01", 33) + "022100" + strings.Repeat("01", 32), wantErr: ErrDERInvalidLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDer(mustDecodeString(tt.in))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseDer() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseDer_strict(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		r, s *big.Int
+	}{
+		{name: "OK if padding keeps r positive", in: "300702020081020101", r: big.NewInt(0x81), s: big.NewInt(1)},
+		{name: "OK if r is a single zero", in: "3006020100020101", r: big.NewInt(0), s: big.NewInt(1)},
+		{
+			name: "OK with 33 byte r and s",
+			in:   "3046022100" + strings.Repeat("ff", 32) + "022100" + strings.Repeat("80", 32),
+			r:    new(big.Int).SetBytes(mustDecodeString(strings.Repeat("ff", 32))),
+			s:    new(big.Int).SetBytes(mustDecodeString(strings.Repeat("80", 32))),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			der := mustDecodeString(tt.in)
+			sig, err := ParseDer(der)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig.r.Cmp(tt.r) != 0 || sig.s.Cmp(tt.s) != 0 {
+				t.Errorf("ParseDer() = r:%x s:%x, want r:%x s:%x", sig.r, sig.s, tt.r, tt.s)
+			}
+			if got := sig.Der(); !bytes.Equal(got, der) {
+				t.Errorf("Signature.Der() = %x, want %x", got, der)
+			}
+		})
+	}
+}
+
+func TestParseDerLax(t *testing.T) {
+	n := genN()
+	tests := []struct {
+		name    string
+		in      string
+		r, s    *big.Int
+		wantErr error
+	}{
+		{name: "OK if strict", in: "3006020164020132", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if sequence length is wrong", in: "3010020164020132", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if sequence length is long form", in: "308106020164020132", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if trailing bytes", in: "3006020164020132deadbeef", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if r length is long form", in: "300702810164020132", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if r length has leading zeros", in: "30090283000001640201 32", r: big.NewInt(100), s: big.NewInt(50)},
+		{
+			name: "OK if r length is 4 bytes with leading zeros",
+			in:   "3029028400000020" + "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" + "020132",
+			r:    mustGetFromHex("0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+			s:    big.NewInt(50),
+		},
+		{
+			name: "OK if r length is 8 bytes with leading zeros",
+			in:   "302d02880000000000000020" + "79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798" + "020132",
+			r:    mustGetFromHex("0x79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"),
+			s:    big.NewInt(50),
+		},
+		{name: "OK if r is negative", in: "3006020181020132", r: big.NewInt(0x81), s: big.NewInt(50)},
+		{name: "OK if r has excess padding", in: "30080204000000640201 32", r: big.NewInt(100), s: big.NewInt(50)},
+		{name: "OK if s is empty", in: "30050201640200", r: big.NewInt(100), s: big.NewInt(0)},
+		{
+			name: "Zero if r is not below n",
+			in:   "3026022100" + n.Text(16) + "020132",
+			r:    big.NewInt(0),
+			s:    big.NewInt(0),
+		},
+		{
+			name: "Zero if s is wider than 32 bytes",
+			in:   "3027020164022201" + strings.Repeat("00", 33),
+			r:    big.NewInt(0),
+			s:    big.NewInt(0),
+		},
+		{name: "NG if empty", in: "", wantErr: ErrDERInvalidHeader},
+		{name: "NG if header is not a sequence", in: "3106020164020132", wantErr: ErrDERInvalidHeader},
+		{name: "NG if only a header", in: "30", wantErr: ErrDERTruncated},
+		{name: "NG if long sequence length is beyond the buffer", in: "3085020164", wantErr: ErrDERTruncated},
+		{name: "NG if r marker is wrong", in: "3006030164020132", wantErr: ErrDERInvalidIntMarker},
+		{name: "NG if r is beyond the buffer", in: "30060209640201", wantErr: ErrDERTruncated},
+		{name: "NG if r length does not fit in 64 bits", in: "3006028801010101010101010164020132", wantErr: ErrDERInvalidLength},
+		{name: "NG if r length is 4 bytes without leading zeros", in: "3009028401000000640201 32", wantErr: ErrDERInvalidLength},
+		{name: "NG if s length is 5 bytes with one leading zero", in: "300a02016402850001000000", wantErr: ErrDERInvalidLength},
+		{name: "NG if s is missing", in: "3003020164", wantErr: ErrDERTruncated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := ParseDerLax(mustDecodeString(strings.Replace(tt.in, " ", "", -1)))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("ParseDerLax() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sig.r.Cmp(tt.r) != 0 || sig.s.Cmp(tt.s) != 0 {
+				t.Errorf("ParseDerLax() = r:%x s:%x, want r:%x s:%x", sig.r, sig.s, tt.r, tt.s)
+			}
+		})
+	}
+}
+
+func FuzzParseDerLax(f *testing.F) {
+	f.Add(mustDecodeString("3006020164020132"))
+	f.Add(mustDecodeString("308106020164020132"))
+	f.Add(mustDecodeString("300702810164020132"))
+	f.Add([]byte{0x30, 0x80, 0x02, 0x88})
+	f.Fuzz(func(t *testing.T, in []byte) {
+		lax, err := ParseDerLax(in)
+		strict, strictErr := ParseDer(in)
+		if strictErr != nil {
+			return
+		}
+		// every strict signature is also a lax one with the same values,
+		// unless r or s is out of range.
+		if err != nil {
+			t.Fatalf("ParseDerLax(%x) error = %v, but ParseDer accepts it", in, err)
+		}
+		if strict.r.Cmp(genN()) >= 0 || strict.s.Cmp(genN()) >= 0 {
+			return
+		}
+		if lax.r.Cmp(strict.r) != 0 || lax.s.Cmp(strict.s) != 0 {
+			t.Errorf("ParseDerLax(%x) = r:%x s:%x, ParseDer = r:%x s:%x", in, lax.r, lax.s, strict.r, strict.s)
+		}
+	})
+}
+
+func FuzzParseDer(f *testing.F) {
+	f.Add(mustDecodeString("3006020164020132"))
+	f.Add(mustDecodeString("3044022037206a0610995c58074999cb9767b87af4c4978db68c06e8e6e81d282047a7c60220467f2f7d5b5b40de2b6c0f3f76b8e9f0ae4b3a3a4f6c53ac6d04f4f7d0b6f7e8"))
+	f.Add([]byte{0x30})
+	f.Add([]byte{0x30, 0xff, 0x02, 0xff})
+	f.Fuzz(func(t *testing.T, in []byte) {
+		sig, err := ParseDer(in)
+		if err != nil {
+			return
+		}
+		if got := sig.Der(); !bytes.Equal(got, in) {
+			t.Errorf("ParseDer(%x).Der() = %x", in, got)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package ecc
+
+import "golang.org/x/xerrors"
+
+const tagTapTweak = "TapTweak"
+
+// TapTweakHash returns t = hash_TapTweak(P || merkleRoot) of BIP341, where P
+// is the x-only internal key. merkleRoot is the root of the script tree, or
+// empty for an output that can only be spent with the key.
+func (s *s256Point) TapTweakHash(merkleRoot []byte) ([]byte, error) {
+	if s.IsInfinity() {
+		return nil, xerrors.New("internal key is infinity")
+	}
+	if len(merkleRoot) != 0 && len(merkleRoot) != 32 {
+		return nil, xerrors.Errorf("merkle root must be 32 bytes, got %d", len(merkleRoot))
+	}
+	t := taggedHash(tagTapTweak, s.XOnly(), merkleRoot)
+	return t[:], nil
+}
+
+// TapTweak returns the taproot output key Q = lift_x(P) + t*G of BIP341
+// for the internal key s, and whether Q has an odd y. The parity goes into
+// the control block of script path spends; the x-only encoding of Q goes
+// into the output script.
+func (s *s256Point) TapTweak(merkleRoot []byte) (*s256Point, bool, error) {
+	t, err := s.TapTweakHash(merkleRoot)
+	if err != nil {
+		return nil, false, err
+	}
+	q, err := s.evenY().TweakAdd(t)
+	if err != nil {
+		return nil, false, err
+	}
+	return q, q.y.IsOdd(), nil
+}
+
+// TapTweak returns the private key of the output key that TapTweak of its
+// public key returns: the secret is negated first if P has an odd y, then
+// t is added. SignSchnorr with the result spends the output by key path.
+func (p *PrivateKey) TapTweak(merkleRoot []byte) (*PrivateKey, error) {
+	t, err := p.p.TapTweakHash(merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	if p.p.y.IsOdd() {
+		return p.Negate().TweakAdd(t)
+	}
+	return p.TweakAdd(t)
+}
@@ -0,0 +1,102 @@
+package ecc
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+)
+
+func TestTapTweak(t *testing.T) {
+	// from the scriptPubKey vectors of BIP341 and the first receive
+	// address of BIP86.
+	tests := []struct {
+		internal   string
+		merkleRoot string
+		tweak      string
+		output     string
+	}{
+		{
+			internal: "d6889cb081036e0faefa3a35157ad71086b123b2b144b649798b494c300a961d",
+			tweak:    "b86e7be8f39bab32a6f2c0443abbc210f0edac0e2c53d501b36b64437d9c6c70",
+			output:   "53a1f6e454df1aa2776a2814a721372d6258050de330b3c6d10ee8f4e0dda343",
+		},
+		{
+			internal:   "187791b6f712a8ea41c8ecdd0ee77fab3e85263b37e1ec18a3651926b3a6cf27",
+			merkleRoot: tapLeafHash(t, "20d85a959b0290bf19bb89ed43c916be835475d013da4b362117393e25a48229b8ac"),
+			tweak:      "cbd8679ba636c1110ea247542cfbd964131a6be84f873f7f3b62a777528ed001",
+			output:     "147c9c57132f6e7ecddba9800bb0c4449251c92a1e60371ee77557b6620f3ea3",
+		},
+		{
+			internal: "cc8a4bc64d897bddc5fbc2f670f7a8ba0b386779106cf1223c6fc5d7cd6fc115",
+			output:   "a60869f0dbcf1dc659c9cecbaf8050135ea9e8cdc487053f1dc6880949dc684c",
+		},
+	}
+	for _, tt := range tests {
+		p, err := ParseXOnly(mustHex(t, tt.internal))
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := mustHex(t, tt.merkleRoot)
+		if tt.tweak != "" {
+			tweak, err := p.TapTweakHash(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(tweak); got != tt.tweak {
+				t.Errorf("TapTweakHash(%s) = %s, want %s", tt.internal, got, tt.tweak)
+			}
+		}
+		q, odd, err := p.TapTweak(root)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(q.XOnly()); got != tt.output {
+			t.Errorf("TapTweak(%s) = %s, want %s", tt.internal, got, tt.output)
+		}
+		if odd != (q.Sec(true)[0] == 0x03) {
+			t.Errorf("TapTweak(%s) parity = %v, want that of %x", tt.internal, odd, q.Sec(true))
+		}
+		// the parity of the internal key does not matter.
+		if q2, _, _ := p.Negate().TapTweak(root); !q2.Eq(q) {
+			t.Errorf("TapTweak(-%s) = %x, want %x", tt.internal, q2.XOnly(), q.XOnly())
+		}
+	}
+}
+
+func tapLeafHash(t *testing.T, script string) string {
+	b := mustHex(t, script)
+	h := taggedHash("TapLeaf", []byte{0xc0, byte(len(b))}, b)
+	return hex.EncodeToString(h[:])
+}
+
+func TestPrivateKeyTapTweak(t *testing.T) {
+	root := mustHex(t, "5b75adecf53548f3ec6ad7d78383bf84cc57b55a3127c72b9a2481752dd88b21")
+	// 1 has an even y and 3 an odd one.
+	for _, secret := range []int64{1, 3} {
+		p, err := NewPrivateKey(big.NewInt(secret))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, merkleRoot := range [][]byte{nil, root} {
+			q, _, err := p.p.TapTweak(merkleRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tweaked, err := p.TapTweak(merkleRoot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tweaked.p.Eq(q) {
+				t.Fatalf("TapTweak(%d) public key = %x, want %x", secret, tweaked.p.Sec(true), q.Sec(true))
+			}
+			msg := []byte("key path spend")
+			sig, err := tweaked.SignSchnorr(msg, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !verifySchnorrBytes(q.XOnly(), msg, sig.Bytes()) {
+				t.Errorf("TapTweak(%d) key cannot sign for the output key", secret)
+			}
+		}
+	}
+}
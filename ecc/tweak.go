@@ -0,0 +1,79 @@
+package ecc
+
+import "golang.org/x/xerrors"
+
+// parseTweak decodes a 32-byte big-endian tweak, which must be below n.
+func parseTweak(tweak []byte) (Scalar, error) {
+	var t Scalar
+	if len(tweak) != 32 {
+		return t, xerrors.Errorf("tweak must be 32 bytes, got %d", len(tweak))
+	}
+	if t.SetByteSlice(tweak) {
+		return t, xerrors.Errorf("tweak is not below n: %w", ErrInvalidTweak)
+	}
+	return t, nil
+}
+
+// newPrivateKeyFromScalar returns the private key for a secret that is
+// already reduced, or ErrInvalidTweak if it is zero.
+func newPrivateKeyFromScalar(secret *Scalar) (*PrivateKey, error) {
+	if secret.IsZero() {
+		return nil, xerrors.Errorf("tweaked private key is zero: %w", ErrInvalidTweak)
+	}
+	p := &PrivateKey{}
+	p.secret.Set(secret)
+	p.p = ScalarBaseMul(&p.secret)
+	return p, nil
+}
+
+// TweakAdd returns the private key secret + t, where t is the 32-byte
+// tweak. Its public key is the one TweakAdd of the public key returns.
+func (p *PrivateKey) TweakAdd(tweak []byte) (*PrivateKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKeyFromScalar(t.Add(&t, &p.secret))
+}
+
+// TweakMul returns the private key secret * t, where t is the 32-byte
+// tweak.
+func (p *PrivateKey) TweakMul(tweak []byte) (*PrivateKey, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	return newPrivateKeyFromScalar(t.Mul(&t, &p.secret))
+}
+
+// Negate returns the private key n - secret, whose public key is -P.
+func (p *PrivateKey) Negate() *PrivateKey {
+	out := &PrivateKey{p: p.p.Negate()}
+	out.secret.Negate(&p.secret)
+	return out
+}
+
+// TweakAdd returns s + t*G, where t is the 32-byte tweak.
+func (s *s256Point) TweakAdd(tweak []byte) (*s256Point, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	q := s.Add(ScalarBaseMul(&t))
+	if q.IsInfinity() {
+		return nil, xerrors.Errorf("tweaked public key is infinity: %w", ErrInvalidTweak)
+	}
+	return q, nil
+}
+
+// TweakMul returns t*s, where t is the 32-byte tweak.
+func (s *s256Point) TweakMul(tweak []byte) (*s256Point, error) {
+	t, err := parseTweak(tweak)
+	if err != nil {
+		return nil, err
+	}
+	if t.IsZero() || s.IsInfinity() {
+		return nil, xerrors.Errorf("tweaked public key is infinity: %w", ErrInvalidTweak)
+	}
+	return s.ScalarMul(&t), nil
+}
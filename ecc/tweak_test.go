@@ -0,0 +1,92 @@
+package ecc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestTweak(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(12345))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tweak := mustHex(t, "00000000000000000000000000000000000000000000000000000000000003e8")
+	want, _ := NewPrivateKey(big.NewInt(12345 + 1000))
+
+	added, err := p.TweakAdd(tweak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !added.p.Eq(want.p) {
+		t.Errorf("TweakAdd() = %x, want %x", added.p.Sec(true), want.p.Sec(true))
+	}
+	pub, err := p.p.TweakAdd(tweak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Eq(want.p) {
+		t.Errorf("public TweakAdd() = %x, want %x", pub.Sec(true), want.p.Sec(true))
+	}
+
+	want, _ = NewPrivateKey(big.NewInt(12345 * 1000))
+	multiplied, err := p.TweakMul(tweak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !multiplied.p.Eq(want.p) {
+		t.Errorf("TweakMul() = %x, want %x", multiplied.p.Sec(true), want.p.Sec(true))
+	}
+	pub, err = p.p.TweakMul(tweak)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !pub.Eq(want.p) {
+		t.Errorf("public TweakMul() = %x, want %x", pub.Sec(true), want.p.Sec(true))
+	}
+
+	neg := p.Negate()
+	if !neg.p.Eq(p.p.Negate()) || !neg.Negate().p.Eq(p.p) {
+		t.Error("Negate() does not negate the public key")
+	}
+	if sum := p.p.Add(neg.p); !sum.IsInfinity() {
+		t.Errorf("P + Negate(P) = %x, want infinity", sum.Sec(true))
+	}
+}
+
+func TestTweakInvalid(t *testing.T) {
+	p, err := NewPrivateKey(big.NewInt(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// n - 1 takes the secret 1 to zero and G to infinity.
+	minusOne := new(big.Int).Sub(genN(), big.NewInt(1)).Bytes()
+	n := genN().Bytes()
+	zero := make([]byte, 32)
+
+	tests := []struct {
+		name  string
+		tweak []byte
+		op    func([]byte) error
+		err   error
+	}{
+		{"add overflow", n, func(b []byte) error { _, err := p.TweakAdd(b); return err }, ErrInvalidTweak},
+		{"add to zero", minusOne, func(b []byte) error { _, err := p.TweakAdd(b); return err }, ErrInvalidTweak},
+		{"add short", zero[:31], func(b []byte) error { _, err := p.TweakAdd(b); return err }, nil},
+		{"mul zero", zero, func(b []byte) error { _, err := p.TweakMul(b); return err }, ErrInvalidTweak},
+		{"mul overflow", n, func(b []byte) error { _, err := p.TweakMul(b); return err }, ErrInvalidTweak},
+		{"public add to infinity", minusOne, func(b []byte) error { _, err := p.p.TweakAdd(b); return err }, ErrInvalidTweak},
+		{"public add overflow", n, func(b []byte) error { _, err := p.p.TweakAdd(b); return err }, ErrInvalidTweak},
+		{"public mul zero", zero, func(b []byte) error { _, err := p.p.TweakMul(b); return err }, ErrInvalidTweak},
+	}
+	for _, tt := range tests {
+		err := tt.op(tt.tweak)
+		if err == nil {
+			t.Errorf("%s: succeeded", tt.name)
+			continue
+		}
+		if tt.err != nil && !errors.Is(err, tt.err) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.err)
+		}
+	}
+}
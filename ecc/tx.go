@@ -0,0 +1,447 @@
+package ecc
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+
+	"golang.org/x/xerrors"
+)
+
+// Sighash types.
+const (
+	sigHashDefault = 0x00
+	sigHashAll     = 0x01
+)
+
+// tx is the small subset of a Bitcoin transaction needed to build and check
+// the virtual transactions of BIP322. Hashes are kept in internal byte
+// order, the reverse of how txids are usually displayed.
+type tx struct {
+	version  int32
+	ins      []txIn
+	outs     []txOut
+	lockTime uint32
+}
+
+type txIn struct {
+	prevHash  [32]byte
+	prevIndex uint32
+	scriptSig []byte
+	sequence  uint32
+	witness   [][]byte
+}
+
+type txOut struct {
+	value  int64
+	script []byte
+}
+
+func (t *tx) hasWitness() bool {
+	for _, in := range t.ins {
+		if len(in.witness) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// serialize returns the network encoding of t. The BIP144 witness format is
+// used when withWitness is set and some input has a witness.
+func (t *tx) serialize(withWitness bool) []byte {
+	withWitness = withWitness && t.hasWitness()
+	var b []byte
+	b = appendUint32(b, uint32(t.version))
+	if withWitness {
+		// marker and flag
+		b = append(b, 0x00, 0x01)
+	}
+	b = appendVarint(b, uint64(len(t.ins)))
+	for _, in := range t.ins {
+		b = append(b, in.prevHash[:]...)
+		b = appendUint32(b, in.prevIndex)
+		b = appendVarBytes(b, in.scriptSig)
+		b = appendUint32(b, in.sequence)
+	}
+	b = appendVarint(b, uint64(len(t.outs)))
+	for _, out := range t.outs {
+		b = appendUint64(b, uint64(out.value))
+		b = appendVarBytes(b, out.script)
+	}
+	if withWitness {
+		for _, in := range t.ins {
+			b = appendWitness(b, in.witness)
+		}
+	}
+	return appendUint32(b, t.lockTime)
+}
+
+// txid returns the double SHA256 of t without witnesses.
+func (t *tx) txid() [32]byte {
+	var id [32]byte
+	copy(id[:], Hash256(t.serialize(false)))
+	return id
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.LittleEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// appendWitness appends a witness stack: the item count followed by every
+// item with its length.
+func appendWitness(b []byte, witness [][]byte) []byte {
+	b = appendVarint(b, uint64(len(witness)))
+	for _, item := range witness {
+		b = appendVarBytes(b, item)
+	}
+	return b
+}
+
+// txReader decodes the network encoding. Every read checks the remaining
+// length, so malformed input gives an error instead of a panic.
+type txReader struct {
+	r *bytes.Reader
+}
+
+func (r *txReader) bytes(n uint64) ([]byte, error) {
+	if n > uint64(r.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(r.r, b)
+	return b, err
+}
+
+func (r *txReader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *txReader) uint64() (uint64, error) {
+	b, err := r.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (r *txReader) varint() (uint64, error) {
+	prefix, err := r.r.ReadByte()
+	if err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	var n uint64
+	switch prefix {
+	case 0xfd:
+		b, err := r.bytes(2)
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(binary.LittleEndian.Uint16(b))
+		if n < 0xfd {
+			return 0, xerrors.New("non-canonical varint")
+		}
+	case 0xfe:
+		v, err := r.uint32()
+		if err != nil {
+			return 0, err
+		}
+		n = uint64(v)
+		if n <= 0xffff {
+			return 0, xerrors.New("non-canonical varint")
+		}
+	case 0xff:
+		n, err = r.uint64()
+		if err != nil {
+			return 0, err
+		}
+		if n <= 0xffffffff {
+			return 0, xerrors.New("non-canonical varint")
+		}
+	default:
+		n = uint64(prefix)
+	}
+	return n, nil
+}
+
+func (r *txReader) varBytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	return r.bytes(n)
+}
+
+func (r *txReader) witness() ([][]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	// every item takes at least one byte.
+	if n > uint64(r.r.Len()) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	witness := make([][]byte, n)
+	for i := range witness {
+		if witness[i], err = r.varBytes(); err != nil {
+			return nil, err
+		}
+	}
+	return witness, nil
+}
+
+// parseWitness decodes a serialized witness stack, which must use up all
+// of b.
+func parseWitness(b []byte) ([][]byte, error) {
+	r := &txReader{bytes.NewReader(b)}
+	witness, err := r.witness()
+	if err != nil {
+		return nil, xerrors.Errorf("malformed witness: %w", err)
+	}
+	if r.r.Len() != 0 {
+		return nil, xerrors.Errorf("malformed witness: %d trailing bytes", r.r.Len())
+	}
+	return witness, nil
+}
+
+// parseTx decodes a transaction in either the legacy or the BIP144 witness
+// format, which must use up all of b.
+func parseTx(b []byte) (*tx, error) {
+	t, err := readTx(&txReader{bytes.NewReader(b)}, len(b))
+	if err != nil {
+		return nil, xerrors.Errorf("malformed transaction: %w", err)
+	}
+	return t, nil
+}
+
+func readTx(r *txReader, size int) (*tx, error) {
+	t := &tx{}
+	version, err := r.uint32()
+	if err != nil {
+		return nil, err
+	}
+	t.version = int32(version)
+	inCount, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	withWitness := false
+	if inCount == 0 {
+		// a zero input count is the segwit marker, followed by flag 1.
+		flag, err := r.r.ReadByte()
+		if err != nil || flag != 0x01 {
+			return nil, xerrors.New("bad witness flag")
+		}
+		withWitness = true
+		if inCount, err = r.varint(); err != nil {
+			return nil, err
+		}
+	}
+	// an input takes at least 41 bytes.
+	if inCount > uint64(size)/41 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	t.ins = make([]txIn, inCount)
+	for i := range t.ins {
+		in := &t.ins[i]
+		prev, err := r.bytes(32)
+		if err != nil {
+			return nil, err
+		}
+		copy(in.prevHash[:], prev)
+		if in.prevIndex, err = r.uint32(); err != nil {
+			return nil, err
+		}
+		if in.scriptSig, err = r.varBytes(); err != nil {
+			return nil, err
+		}
+		if in.sequence, err = r.uint32(); err != nil {
+			return nil, err
+		}
+	}
+	outCount, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+	// an output takes at least 9 bytes.
+	if outCount > uint64(size)/9 {
+		return nil, io.ErrUnexpectedEOF
+	}
+	t.outs = make([]txOut, outCount)
+	for i := range t.outs {
+		value, err := r.uint64()
+		if err != nil {
+			return nil, err
+		}
+		t.outs[i].value = int64(value)
+		if t.outs[i].script, err = r.varBytes(); err != nil {
+			return nil, err
+		}
+	}
+	if withWitness {
+		for i := range t.ins {
+			if t.ins[i].witness, err = r.witness(); err != nil {
+				return nil, err
+			}
+		}
+		if !t.hasWitness() {
+			return nil, xerrors.New("witness flag set but no witness")
+		}
+	}
+	if t.lockTime, err = r.uint32(); err != nil {
+		return nil, err
+	}
+	if r.r.Len() != 0 {
+		return nil, xerrors.Errorf("%d trailing bytes", r.r.Len())
+	}
+	return t, nil
+}
+
+// sigHashLegacy returns the pre-segwit SIGHASH_ALL digest of input idx,
+// whose previous output script is scriptCode. OP_CODESEPARATOR is not
+// supported.
+func (t *tx) sigHashLegacy(idx int, scriptCode []byte) []byte {
+	c := *t
+	c.ins = make([]txIn, len(t.ins))
+	for i, in := range t.ins {
+		c.ins[i] = txIn{prevHash: in.prevHash, prevIndex: in.prevIndex, sequence: in.sequence}
+	}
+	c.ins[idx].scriptSig = scriptCode
+	b := appendUint32(c.serialize(false), sigHashAll)
+	return Hash256(b)
+}
+
+// sigHashWitnessV0 returns the BIP143 SIGHASH_ALL digest of input idx,
+// which spends amount with scriptCode.
+// https://github.com/bitcoin/bips/blob/master/bip-0143.mediawiki
+func (t *tx) sigHashWitnessV0(idx int, scriptCode []byte, amount int64) []byte {
+	var prevouts, sequences, outputs []byte
+	for _, in := range t.ins {
+		prevouts = append(prevouts, in.prevHash[:]...)
+		prevouts = appendUint32(prevouts, in.prevIndex)
+		sequences = appendUint32(sequences, in.sequence)
+	}
+	for _, out := range t.outs {
+		outputs = appendUint64(outputs, uint64(out.value))
+		outputs = appendVarBytes(outputs, out.script)
+	}
+	in := t.ins[idx]
+	var b []byte
+	b = appendUint32(b, uint32(t.version))
+	b = append(b, Hash256(prevouts)...)
+	b = append(b, Hash256(sequences)...)
+	b = append(b, in.prevHash[:]...)
+	b = appendUint32(b, in.prevIndex)
+	b = appendVarBytes(b, scriptCode)
+	b = appendUint64(b, uint64(amount))
+	b = appendUint32(b, in.sequence)
+	b = append(b, Hash256(outputs)...)
+	b = appendUint32(b, t.lockTime)
+	b = appendUint32(b, sigHashAll)
+	return Hash256(b)
+}
+
+// sigHashTaproot returns the BIP341 key path digest of input idx for
+// SIGHASH_DEFAULT or SIGHASH_ALL, which commit to the same data. prevouts
+// are the outputs the inputs spend, in order. Annexes are not supported.
+// https://github.com/bitcoin/bips/blob/master/bip-0341.mediawiki#common-signature-message
+func (t *tx) sigHashTaproot(idx int, prevouts []txOut, hashType byte) []byte {
+	var outpoints, amounts, scripts, sequences, outputs []byte
+	for _, in := range t.ins {
+		outpoints = append(outpoints, in.prevHash[:]...)
+		outpoints = appendUint32(outpoints, in.prevIndex)
+		sequences = appendUint32(sequences, in.sequence)
+	}
+	for _, out := range prevouts {
+		amounts = appendUint64(amounts, uint64(out.value))
+		scripts = appendVarBytes(scripts, out.script)
+	}
+	for _, out := range t.outs {
+		outputs = appendUint64(outputs, uint64(out.value))
+		outputs = appendVarBytes(outputs, out.script)
+	}
+	// sighash epoch 0
+	b := []byte{0x00, hashType}
+	b = appendUint32(b, uint32(t.version))
+	b = appendUint32(b, t.lockTime)
+	b = append(b, sha256Sum(outpoints)...)
+	b = append(b, sha256Sum(amounts)...)
+	b = append(b, sha256Sum(scripts)...)
+	b = append(b, sha256Sum(sequences)...)
+	b = append(b, sha256Sum(outputs)...)
+	// spend type: key path without annex
+	b = append(b, 0x00)
+	b = appendUint32(b, uint32(idx))
+	h := taggedHash("TapSighash", b)
+	return h[:]
+}
+
+func sha256Sum(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
+
+// appendPushData appends the minimal script push of data.
+func appendPushData(script, data []byte) []byte {
+	switch n := len(data); {
+	case n < 0x4c:
+		script = append(script, byte(n))
+	case n <= 0xff:
+		script = append(script, 0x4c, byte(n))
+	case n <= 0xffff:
+		script = append(script, 0x4d, byte(n), byte(n>>8))
+	default:
+		script = appendUint32(append(script, 0x4e), uint32(n))
+	}
+	return append(script, data...)
+}
+
+// parsePushes splits a script made only of data pushes into the pushed
+// items, as a scriptSig is required to be.
+func parsePushes(script []byte) ([][]byte, error) {
+	var items [][]byte
+	for i := 0; i < len(script); {
+		op := script[i]
+		i++
+		var n int
+		switch {
+		case op == 0x00:
+			items = append(items, nil)
+			continue
+		case op < 0x4c:
+			n = int(op)
+		case op == 0x4c:
+			if i+1 > len(script) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			n = int(script[i])
+			i++
+		case op == 0x4d:
+			if i+2 > len(script) {
+				return nil, io.ErrUnexpectedEOF
+			}
+			n = int(binary.LittleEndian.Uint16(script[i:]))
+			i += 2
+		default:
+			return nil, xerrors.Errorf("opcode %#x is not a push", op)
+		}
+		if n > len(script)-i {
+			return nil, io.ErrUnexpectedEOF
+		}
+		items = append(items, script[i:i+n])
+		i += n
+	}
+	return items, nil
+}
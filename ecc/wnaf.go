@@ -0,0 +1,125 @@
+package ecc
+
+import (
+	"math/big"
+	"sync"
+)
+
+const (
+	// wnafWindowG is the wNAF width used for G, whose odd multiples are
+	// precomputed once per process.
+	wnafWindowG = 8
+	// wnafWindowP is the wNAF width used for an arbitrary point, whose odd
+	// multiples have to be computed on every call.
+	wnafWindowP = 5
+)
+
+var (
+	wnafGTableOnce sync.Once
+	// wnafGTable[i] holds (2*i+1) * G in affine coordinates.
+	wnafGTable []s256Point
+)
+
+// getWnafGTable builds the table of odd multiples of G on first use.
+func getWnafGTable() []s256Point {
+	wnafGTableOnce.Do(func() {
+		g, err := genG()
+		if err != nil {
+			// G is a constant, so this can only fail if the source is broken.
+			panic(err)
+		}
+		wnafGTable = oddMultiples(g, wnafWindowG)
+	})
+	return wnafGTable
+}
+
+// oddMultiples returns P, 3P, 5P, ..., (2**(w-1)-1)P in affine coordinates.
+func oddMultiples(p *s256Point, w uint) []s256Point {
+	count := 1 << (w - 2)
+	points := make([]s256JacobianPoint, count)
+	var double s256JacobianPoint
+	points[0].setAffine(p)
+	double.double(&points[0])
+	for i := 1; i < count; i++ {
+		points[i].add(&points[i-1], &double)
+	}
+	table := make([]s256Point, count)
+	normalizeS256Batch(points, func(i int, x, y *s256FieldElement) {
+		xi, yi := *x, *y
+		table[i] = s256Point{&xi, &yi, nil}
+	})
+	return table
+}
+
+// wnaf returns the width-w non-adjacent form of k, least significant digit
+// first. Every non-zero digit is odd and lies in (-2**(w-1), 2**(w-1)), and
+// any w consecutive digits hold at most one non-zero digit.
+func wnaf(k *big.Int, w uint) []int {
+	k = new(big.Int).Set(k)
+	window := new(big.Int).Lsh(big.NewInt(1), w)
+	mask := new(big.Int).Sub(window, big.NewInt(1))
+	half := int64(1) << (w - 1)
+	naf := make([]int, 0, k.BitLen()+1)
+	digit := new(big.Int)
+	for k.Sign() > 0 {
+		var d int64
+		if k.Bit(0) == 1 {
+			d = digit.And(k, mask).Int64()
+			if d >= half {
+				d -= int64(1) << w
+			}
+			k.Sub(k, big.NewInt(d))
+		}
+		naf = append(naf, int(d))
+		k.Rsh(k, 1)
+	}
+	return naf
+}
+
+// addWnafDigit adds d * P to acc, where table holds the odd multiples of P.
+func addWnafDigit(acc *s256JacobianPoint, table []s256Point, d int) {
+	switch {
+	case d > 0:
+		acc.addMixed(acc, &table[(d-1)/2])
+	case d < 0:
+		entry := &table[(-d-1)/2]
+		neg := s256Point{entry.x, new(s256FieldElement).Neg(entry.y), nil}
+		acc.addMixed(acc, &neg)
+	}
+}
+
+// DoubleScalarMul returns u*G + v*p. Both products share one doubling chain
+// by interleaving the wNAF digits of u and v (Strauss-Shamir), which makes it
+// much faster than computing them separately.
+//
+// It runs in variable time and is intended for verification, where all the
+// inputs are public.
+func DoubleScalarMul(u, v *big.Int, p *s256Point) *s256Point {
+	n := genN()
+	nafU := wnaf(new(big.Int).Mod(u, n), wnafWindowG)
+	nafV := wnaf(new(big.Int).Mod(v, n), wnafWindowP)
+	tableG := getWnafGTable()
+	var tableP []s256Point
+	if !p.IsInfinity() && len(nafV) > 0 {
+		tableP = oddMultiples(p, wnafWindowP)
+	} else {
+		nafV = nil
+	}
+
+	length := len(nafU)
+	if len(nafV) > length {
+		length = len(nafV)
+	}
+	var acc s256JacobianPoint
+	acc.setInfinity()
+	for i := length - 1; i >= 0; i-- {
+		acc.double(&acc)
+		if i < len(nafU) {
+			addWnafDigit(&acc, tableG, nafU[i])
+		}
+		if i < len(nafV) {
+			addWnafDigit(&acc, tableP, nafV[i])
+		}
+	}
+	return newS256PointFromJacobian(&acc)
+}
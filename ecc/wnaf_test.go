@@ -0,0 +1,91 @@
+package ecc
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func Test_wnaf(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, w := range []uint{2, 5, 8} {
+		for i := 0; i < 50; i++ {
+			k := new(big.Int).Rand(r, genN())
+			naf := wnaf(k, w)
+			got := new(big.Int)
+			lastNonZero := -int(w)
+			for j := len(naf) - 1; j >= 0; j-- {
+				got.Lsh(got, 1).Add(got, big.NewInt(int64(naf[j])))
+			}
+			for j, d := range naf {
+				if d == 0 {
+					continue
+				}
+				if d%2 == 0 || d >= 1<<(w-1) || d <= -(1<<(w-1)) {
+					t.Fatalf("wnaf(%x, %d) has invalid digit %d", k, w, d)
+				}
+				if j-lastNonZero < int(w) {
+					t.Fatalf("wnaf(%x, %d) has non-zero digits closer than %d", k, w, w)
+				}
+				lastNonZero = j
+			}
+			if got.Cmp(k) != 0 {
+				t.Fatalf("wnaf(%x, %d) reconstructs to %x", k, w, got)
+			}
+		}
+	}
+}
+
+func TestDoubleScalarMul(t *testing.T) {
+	g, err := genG()
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := genN()
+	p := g.SRMul(big.NewInt(1485))
+	r := rand.New(rand.NewSource(1))
+	tests := []struct {
+		name string
+		u    *big.Int
+		v    *big.Int
+		p    *s256Point
+	}{
+		{name: "both zero", u: big.NewInt(0), v: big.NewInt(0), p: p},
+		{name: "u zero", u: big.NewInt(0), v: big.NewInt(3), p: p},
+		{name: "v zero", u: big.NewInt(3), v: big.NewInt(0), p: p},
+		{name: "p infinity", u: big.NewInt(5), v: big.NewInt(3), p: S256Infinity()},
+		{name: "sum is infinity", u: new(big.Int).Sub(n, big.NewInt(1485)), v: big.NewInt(1), p: p},
+		{name: "p is G", u: big.NewInt(7), v: big.NewInt(9), p: g},
+		{name: "scalars above n", u: new(big.Int).Add(n, big.NewInt(2)), v: new(big.Int).Add(n, big.NewInt(5)), p: p},
+		{name: "random", u: new(big.Int).Rand(r, n), v: new(big.Int).Rand(r, n), p: g.SRMul(new(big.Int).Rand(r, n))},
+		{name: "random2", u: new(big.Int).Rand(r, n), v: new(big.Int).Rand(r, n), p: g.SRMul(new(big.Int).Rand(r, n))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := baseMul(tt.u).Add(tt.p.SRMul(tt.v))
+			if got := DoubleScalarMul(tt.u, tt.v, tt.p); !got.Eq(want) {
+				t.Errorf("DoubleScalarMul() = x:%v y:%v want x:%v y:%v", got.x, got.y, want.x, want.y)
+			}
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	s, err := NewS256Point(
+		mustGetFromHex("0x887387e452b8eacc4acfde10d9aaf7f6d9a0f975aabb10d006e4da568744d06c"),
+		mustGetFromHex("0x61de6d95231cd89026e286df3b6ae4a894a3378e393e93a0f45b666329a0ae34"),
+	)
+	if err != nil {
+		b.Fatal(err)
+	}
+	z := mustGetFromHex("0xec208baa0fc1c19f708a9ca96fdeff3ac3f230bb4a7ba4aede4942ad003c0f60")
+	sig := Signature{
+		r: mustGetFromHex("0xac8d1c87e51d0d441be8b3dd5b05c8795b48875dffe00b7ffcfac23010d3a395"),
+		s: mustGetFromHex("0x68342ceff8935ededd102dd876ffd6ba72d6a427a3edb13d26eb0781cb423c4"),
+	}
+	for i := 0; i < b.N; i++ {
+		if ok, err := s.Verify(z, sig); err != nil || !ok {
+			b.Fatal("verification failed")
+		}
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/YusukeShimizu/c-go-bitcoin
 
-go 1.15
+go 1.18
 
 require (
 	github.com/btcsuite/btcd v0.20.1-beta
@@ -9,3 +9,8 @@ require (
 	golang.org/x/crypto v0.0.0-20201221181555-eec23a3978ad
 	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1
 )
+
+require (
+	github.com/decred/dcrd/dcrec/secp256k1/v2 v2.0.0 // indirect
+	golang.org/x/sys v0.0.0-20191026070338-33540a1f6037 // indirect
+)